@@ -8,4 +8,5 @@ require (
 	github.com/matryer/is v1.4.0
 	github.com/olekukonko/tablewriter v0.0.4
 	github.com/xwb1989/sqlparser v0.0.0-20180606152119-120387863bf2
+	gopkg.in/yaml.v2 v2.4.0
 )
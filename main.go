@@ -5,80 +5,33 @@
 package main
 
 import (
-	"flag"
-	"io"
 	"log"
 	"os"
 
-	"github.com/rvflash/ds/internal/mysql"
-)
-
-const (
-	subCmd = iota + 1
-	filePath
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+	"github.com/rvflash/ds/pkg/server"
 )
 
 // Filled by the CI when building.
 var buildVersion string
 
 func main() {
-	var (
-		c1c = new(mysql.Config)
-		c1f = flag.NewFlagSet(mysql.Command, flag.ExitOnError)
-		w   = log.New(os.Stderr, "ds: ", 0)
-		s   = "batch mode, print results using comma as the column separator, with each row on a new line"
+	w := log.New(os.Stderr, "ds: ", 0)
+	r, err := ds.NewRegistry(
+		mysql.NewCLI(os.Stderr),
+		server.NewCLI(),
 	)
-	c1f.BoolVar(&c1c.Batch, "B", false, s)
-	s = "verbose mode, produce more output about what the program does"
-	c1f.BoolVar(&c1c.Verbose, "v", false, s)
-	s = "number of decimals to display"
-	c1f.Uint64Var(&c1c.Precision, "p", mysql.DefaultPrecision, s)
-	s = "number of lines to considerate by table"
-	c1f.Uint64Var(&c1c.PerN, "n", mysql.DefaultPerN, s)
-
-	var cmdName string
-	if len(os.Args) > subCmd {
-		cmdName = os.Args[subCmd]
+	if err != nil {
+		w.Fatal(err.Error())
 	}
-	switch cmdName {
-	case mysql.Command:
-		err := c1f.Parse(os.Args[filePath:])
-		if err != nil {
-			w.Fatal(err.Error())
-		}
-		e, err := mysql.Estimate(
-			mysql.SetPrecision(c1c.Precision),
-			mysql.SetPerN(c1c.PerN),
-			mysql.SetBatchMode(c1c.Batch),
-			mysql.SetVerbose(c1c.Verbose),
-		)
-		if err != nil {
-			w.Fatal(err.Error())
-		}
-		r, err := openReader(os.Stdin, c1f.Args())
-		if err != nil {
-			w.Fatal(err.Error())
-		}
-		err = e.Run(r, os.Stdout)
-		if err != nil {
-			w.Fatal(err.Error())
-		}
-	default:
+	if len(os.Args) < 2 {
 		w.Printf("version %s\n", buildVersion)
-		if cmdName != "" {
-			w.Fatalf("unsupported command named %q", cmdName)
-		}
-		w.Printf("available sub commands:\n    - %s\n", mysql.Command)
+		_ = r.Help(os.Stderr)
+		return
 	}
-}
-
-func openReader(r io.Reader, args []string) (io.Reader, error) {
-	if len(args) > 0 {
-		f, err := os.Open(args[0])
-		if err != nil {
-			return nil, err
-		}
-		return f, nil
+	err = r.Run(os.Args[1:], os.Stdin, os.Stdout)
+	if err != nil {
+		w.Fatal(err.Error())
 	}
-	return r, nil
 }
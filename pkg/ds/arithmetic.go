@@ -0,0 +1,60 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds
+
+import (
+	"math"
+	"math/bits"
+)
+
+// Unbounded is the size of a data without upper limit or too large to be represented.
+// Any addition or multiplication with it remains unbounded.
+const Unbounded uint64 = math.MaxUint64
+
+// Add returns the sum of the sizes, saturated to Unbounded instead of overflowing.
+func Add(sizes ...uint64) uint64 {
+	var (
+		res, carry uint64
+	)
+	for _, s := range sizes {
+		res, carry = bits.Add64(res, s, 0)
+		if carry > 0 {
+			return Unbounded
+		}
+	}
+	return res
+}
+
+// Mul returns the product of the sizes, saturated to Unbounded instead of overflowing.
+func Mul(a, b uint64) uint64 {
+	if a == Unbounded && b > 0 || b == Unbounded && a > 0 {
+		return Unbounded
+	}
+	hi, lo := bits.Mul64(a, b)
+	if hi > 0 {
+		return Unbounded
+	}
+	return lo
+}
+
+// Sum returns the sum of the minimum and maximum sizes of each data, saturated to Unbounded.
+func Sum(data ...Data) (min, max uint64) {
+	var n, x uint64
+	for _, d := range data {
+		n, x = d.Size()
+		min, max = Add(min, n), Add(max, x)
+	}
+	return
+}
+
+// Scale returns the size of the data for the given number of rows, saturated to Unbounded.
+// If the data implements the Scaler interface, it is used.
+func Scale(d Data, rows uint64) (min, max uint64) {
+	if s, ok := d.(Scaler); ok {
+		return s.Scale(rows)
+	}
+	min, max = d.Size()
+	return Mul(min, rows), Mul(max, rows)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	ds_mock "github.com/rvflash/ds/testdata/mock/ds"
+)
+
+func TestAdd(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  []uint64
+			out uint64
+		}{
+			"Default":   {},
+			"One":       {in: []uint64{12}, out: 12},
+			"Many":      {in: []uint64{12, 24, 6}, out: 42},
+			"Max":       {in: []uint64{math.MaxUint64 - 1, 1}, out: ds.Unbounded},
+			"Overflow":  {in: []uint64{math.MaxUint64 - 1, 2}, out: ds.Unbounded},
+			"Unbounded": {in: []uint64{ds.Unbounded, 0}, out: ds.Unbounded},
+			"Carry":     {in: []uint64{math.MaxUint64 / 2, math.MaxUint64 / 2, 2, 1}, out: ds.Unbounded},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			are.Equal(tt.out, ds.Add(tt.in...)) // mismatch result
+		})
+	}
+}
+
+func TestMul(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			a, b, out uint64
+		}{
+			"Default":   {},
+			"Zero":      {a: ds.Unbounded},
+			"OK":        {a: 6, b: 7, out: 42},
+			"Overflow":  {a: math.MaxUint32 + 1, b: math.MaxUint32 + 1, out: ds.Unbounded},
+			"Unbounded": {a: 1, b: ds.Unbounded, out: ds.Unbounded},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			are.Equal(tt.out, ds.Mul(tt.a, tt.b)) // mismatch result
+		})
+	}
+}
+
+func TestSum(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	d0 := ds_mock.NewMockData(ctrl)
+	d0.EXPECT().Size().Return(uint64(1), uint64(math.MaxUint64-1)).AnyTimes()
+
+	are := is.New(t)
+	min, max := ds.Sum()
+	are.Equal(min, uint64(0)) // mismatch default minimum
+	are.Equal(max, uint64(0)) // mismatch default maximum
+	min, max = ds.Sum(d0, d0)
+	are.Equal(min, uint64(2))    // mismatch minimum
+	are.Equal(max, ds.Unbounded) // mismatch maximum
+}
+
+func TestScale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	d0 := ds_mock.NewMockData(ctrl)
+	d0.EXPECT().Size().Return(uint64(2), uint64(math.MaxUint32)).AnyTimes()
+
+	type scaler struct {
+		*ds_mock.MockData
+		*ds_mock.MockScaler
+	}
+	s0 := ds_mock.NewMockScaler(ctrl)
+	s0.EXPECT().Scale(uint64(10)).Return(uint64(3), uint64(4)).Times(1)
+
+	are := is.New(t)
+	min, max := ds.Scale(d0, 10)
+	are.Equal(min, uint64(20))                // mismatch minimum
+	are.Equal(max, uint64(math.MaxUint32)*10) // mismatch maximum
+	min, max = ds.Scale(d0, math.MaxUint32+2)
+	are.Equal(min, uint64(math.MaxUint32+2)*2) // mismatch large minimum
+	are.Equal(max, ds.Unbounded)               // mismatch large maximum
+	min, max = ds.Scale(scaler{MockData: d0, MockScaler: s0}, 10)
+	are.Equal(min, uint64(3)) // mismatch scaled minimum
+	are.Equal(max, uint64(4)) // mismatch scaled maximum
+}
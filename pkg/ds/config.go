@@ -0,0 +1,178 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds
+
+import (
+	"encoding"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFile is the name of the configuration file of a project, searched from the working directory upward.
+// It contains a section by estimator, named as it, like:
+//
+//	mysql:
+//	  verbose: true
+//	  rows: [1e6, 1e8, 1e9]
+//
+// The settings of the file are overridden by the flags, themselves overridden by the environment variables,
+// named with the EnvPrefix, the name of the estimator and the key of the setting, like DS_MYSQL_VERBOSE.
+const ConfigFile = ".ds.yaml"
+
+// Environment variables.
+const (
+	// EnvPrefix is the prefix of the environment variables overriding the settings.
+	EnvPrefix = "DS_"
+	// EnvConfig is the environment variable with the path of the configuration file.
+	EnvConfig = EnvPrefix + "CONFIG"
+)
+
+// FindConfig returns the path of the first configuration file found from this directory upward, if any.
+func FindConfig(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		path := filepath.Join(dir, ConfigFile)
+		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// section is a section of the configuration file, decoded on demand.
+type section struct {
+	unmarshal func(interface{}) error
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (s *section) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	s.unmarshal = unmarshal
+	return nil
+}
+
+// readConfig decodes the section of the configuration file named as the estimator into its settings.
+// Any unknown key is an error.
+func readConfig(path string, e Configurable, name string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var res map[string]*section
+	err = yaml.UnmarshalStrict(b, &res)
+	if err != nil {
+		return WrapErr("config "+path+": "+err.Error(), ErrInvalid)
+	}
+	s, ok := res[name]
+	if !ok || s == nil || s.unmarshal == nil {
+		return nil
+	}
+	err = s.unmarshal(e.Settings())
+	if err != nil {
+		return WrapErr("config "+path+": "+err.Error(), ErrInvalid)
+	}
+	return nil
+}
+
+// readEnv overrides the settings of the estimator with the environment variables,
+// named with the prefix, the name of the estimator and the yaml tag of each setting in upper case.
+func readEnv(e Configurable, name string) error {
+	err := Configure(e.Settings(), func(key string) (string, bool) {
+		return os.LookupEnv(EnvPrefix + strings.ToUpper(name+"_"+key))
+	})
+	if err != nil {
+		return WrapErr("environment", err)
+	}
+	return nil
+}
+
+// Configure sets the settings, a pointer to a struct, with the values returned by the lookup function
+// for the yaml tags of its fields, like the environment variables or the values of a form.
+// The values are parsed as the flags, the fields without value being unchanged.
+func Configure(settings interface{}, lookup func(key string) (string, bool)) error {
+	v := reflect.ValueOf(settings)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return WrapErr("settings", ErrInvalid)
+	}
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		key := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		s, ok := lookup(key)
+		if !ok {
+			continue
+		}
+		err := setValue(v.Field(i), s)
+		if err != nil {
+			return WrapErr("setting "+key, err)
+		}
+	}
+	return nil
+}
+
+// setValue sets the value of the field based on its string representation.
+func setValue(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return ErrInvalid
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return ErrInvalid
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, base10, v.Type().Bits())
+		if err != nil {
+			return ErrInvalid
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(s, base10, v.Type().Bits())
+		if err != nil {
+			return ErrInvalid
+		}
+		v.SetUint(i)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return ErrInvalid
+		}
+		v.SetFloat(f)
+		return nil
+	default:
+		return ErrInvalid
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	ds_mock "github.com/rvflash/ds/testdata/mock/ds"
+)
+
+// settings are the settings of a configurable estimator.
+type settings struct {
+	Verbose bool         `yaml:"verbose"`
+	Rows    ds.Scenarios `yaml:"rows"`
+	Label   string       `yaml:"name"`
+	Size    ds.Unit      `yaml:"size"`
+	Ratio   float64      `yaml:"ratio"`
+}
+
+// configurable is an estimator with settings.
+type configurable struct {
+	*ds_mock.MockEstimator
+	settings
+}
+
+func (c *configurable) Settings() interface{} {
+	return &c.settings
+}
+
+func writeConfig(t *testing.T, dir, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, ds.ConfigFile)
+	err := ioutil.WriteFile(path, []byte(data), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFindConfig(t *testing.T) {
+	are := is.New(t)
+	dir, err := ioutil.TempDir("", "ds")
+	are.NoErr(err) // unexpected temp dir error
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	sub := filepath.Join(dir, "a", "b")
+	are.NoErr(os.MkdirAll(sub, 0o700)) // unexpected mkdir error
+	_, ok := ds.FindConfig(sub)
+	are.True(!ok) // unexpected config file
+	path := writeConfig(t, dir, "")
+	res, ok := ds.FindConfig(sub)
+	are.True(ok)         // expected config file
+	are.Equal(path, res) // mismatch path
+}
+
+func TestRegistry_Run_Config(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	are := is.New(t)
+	dir, err := ioutil.TempDir("", "ds")
+	are.NoErr(err) // unexpected temp dir error
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	e := &configurable{MockEstimator: newEstimator(ctrl, "a")}
+	e.EXPECT().SetFlags(gomock.Any()).Do(func(fs *flag.FlagSet) {
+		e.settings = settings{Label: "default", Ratio: 0.5}
+		fs.BoolVar(&e.Verbose, "v", false, "verbose mode")
+		fs.StringVar(&e.Label, "name", e.Label, "name")
+	}).AnyTimes()
+	e.EXPECT().Run(gomock.Any(), gomock.Any()).DoAndReturn(func(r io.Reader, w io.Writer) error {
+		return nil
+	}).AnyTimes()
+	r, err := ds.NewRegistry(e)
+	are.NoErr(err) // unexpected registry error
+
+	path := writeConfig(t, dir, "a:\n  verbose: true\n  rows: [1e6, 100]\n  name: file\n  size: 32KiB\nb:\n  any: 1\n")
+	are.NoErr(r.Run([]string{"-config", path, "a", "-name", "flag"}, nil, new(bytes.Buffer))) // unexpected run error
+	are.Equal(settings{Verbose: true, Rows: ds.Scenarios{1000000, 100}, Label: "flag", Size: 32 * ds.KibiByte, Ratio: 0.5}, e.settings)
+
+	are.NoErr(os.Setenv("DS_A_NAME", "env"))                                                  // unexpected env error
+	are.NoErr(os.Setenv("DS_A_ROWS", "1e3,1e4"))                                              // unexpected env error
+	are.NoErr(os.Setenv("DS_A_RATIO", "0.2"))                                                 // unexpected env error
+	defer func() { _ = os.Unsetenv("DS_A_NAME") }()                                           // clean up
+	defer func() { _ = os.Unsetenv("DS_A_ROWS") }()                                           // clean up
+	defer func() { _ = os.Unsetenv("DS_A_RATIO") }()                                          // clean up
+	are.NoErr(r.Run([]string{"-config", path, "a", "-name", "flag"}, nil, new(bytes.Buffer))) // unexpected run error
+	are.Equal(settings{Verbose: true, Rows: ds.Scenarios{1000, 10000}, Label: "env", Size: 32 * ds.KibiByte, Ratio: 0.2}, e.settings)
+
+	are.NoErr(os.Setenv("DS_A_VERBOSE", "maybe"))                                                     // unexpected env error
+	defer func() { _ = os.Unsetenv("DS_A_VERBOSE") }()                                                // clean up
+	are.True(errors.Is(r.Run([]string{"-config", path, "a"}, nil, new(bytes.Buffer)), ds.ErrInvalid)) // expected invalid env
+
+	path = writeConfig(t, dir, "a:\n  unknown: true\n")
+	are.True(errors.Is(r.Run([]string{"-config", path, "a"}, nil, new(bytes.Buffer)), ds.ErrInvalid))         // expected unknown key
+	are.True(r.Run([]string{"-config", filepath.Join(dir, "none.yaml"), "a"}, nil, new(bytes.Buffer)) != nil) // expected missing file
+}
+
+func TestConfigure(t *testing.T) {
+	var (
+		are    = is.New(t)
+		values = map[string]string{"rows": "1e3", "size": "1KB", "ratio": "0.3"}
+		lookup = func(key string) (string, bool) {
+			v, ok := values[key]
+			return v, ok
+		}
+		s = settings{Label: "default"}
+	)
+	are.NoErr(ds.Configure(&s, lookup)) // unexpected configure error
+	are.Equal(settings{Rows: ds.Scenarios{1000}, Label: "default", Size: ds.KiloByte, Ratio: 0.3}, s)
+
+	values["verbose"] = "maybe"
+	are.True(errors.Is(ds.Configure(&s, lookup), ds.ErrInvalid)) // expected invalid value
+	are.True(errors.Is(ds.Configure(s, lookup), ds.ErrInvalid))  // expected pointer
+}
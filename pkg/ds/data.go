@@ -6,6 +6,7 @@
 package ds
 
 import (
+	"flag"
 	"fmt"
 	"io"
 )
@@ -24,8 +25,74 @@ type Data interface {
 	fmt.Stringer
 }
 
-// Estimator must be implemented by any data size estimator.
-type Estimator func(io.Reader, io.Writer) error
+// Scaler may be implemented by any data whose size is not proportional to its number of rows.
+// Scale returns the size of the data for the given number of rows.
+type Scaler interface {
+	Scale(rows uint64) (min, max uint64)
+}
+
+// Distributor may be implemented by any data whose size distribution is known.
+// Distribution returns the distribution of the size by row.
+type Distributor interface {
+	Distribution() Distribution
+}
+
+// Node may be implemented by any data composed of other data, like a table with its columns.
+// Children returns the data composing it.
+type Node interface {
+	Data
+	Children() []Data
+}
+
+// Grouper may be implemented by any node whose children are split into groups, like the data and the indexes of a table.
+// Groups returns them, each group being a node composed of some of the children.
+type Grouper interface {
+	Groups() []Node
+}
+
+// Detailer may be implemented by any data with additional information about its size,
+// like its footprint in memory. Details returns them.
+type Detailer interface {
+	Details() []Data
+}
+
+// Flagger may be implemented by any data whose size is partial or excluded from the estimation.
+// Flagged returns true in such a case.
+type Flagger interface {
+	Flagged() bool
+}
+
+// Describer may be implemented by any data with properties, like the engine of a table or the charset of a column.
+// Property returns the value of the named property, empty if the data has not this property.
+type Describer interface {
+	Property(name string) string
+}
+
+// Estimator must be implemented by any data size estimator, to be run as a subcommand.
+// Name returns the name of the subcommand and Usage a short description of it.
+// SetFlags defines its flags in the flag set, parsed before calling Run
+// with the named file (or standard input) to estimate and the writer of the report.
+type Estimator interface {
+	Name() string
+	Usage() string
+	SetFlags(fs *flag.FlagSet)
+	Run(r io.Reader, w io.Writer) error
+}
+
+// Configurable may be implemented by any estimator whose settings can also be read from the configuration file
+// and from the environment. Settings returns a pointer to the struct receiving them, the yaml tags of its fields
+// being their keys in the section of the file named as the estimator, like in the environment variables.
+type Configurable interface {
+	Settings() interface{}
+}
+
+// Watcher may be implemented by any estimator able to watch the files to estimate, to estimate them again on changes.
+// If Watching returns true once the flags parsed, Watch is called instead of Run,
+// with the paths of the files or directories following the flags.
+type Watcher interface {
+	Watching() bool
+	Watch(paths []string, w io.Writer) error
+}
 
 type data struct {
 	Data
@@ -34,5 +101,22 @@ type data struct {
 
 // Size overloads the Size method to force new min and max sizes.
 func (d data) Size() (min, max uint64) {
-	return d.min, d.min
+	return d.min, d.max
+}
+
+// Flagged implements the Flagger interface.
+func (d data) Flagged() bool {
+	return Flagged(d.Data)
+}
+
+// Property implements the Describer interface.
+func (d data) Property(name string) string {
+	return Property(d.Data, name)
+}
+
+// Distribution implements the Distributor interface.
+// The distribution of the data is fitted to the new minimum and maximum sizes.
+func (d data) Distribution() Distribution {
+	min, max := d.Data.Size()
+	return Expect(d.Data).Fit(min, max, d.min, d.max)
 }
@@ -32,6 +32,8 @@ func TestNewDataSize(t *testing.T) {
 	na, nb := d1.Size()
 	are.True(oa != na)                  // expected different minimum value
 	are.True(ob != nb)                  // expected different maximum value
+	are.Equal(na, uint64(c))            // mismatch minimum value
+	are.Equal(nb, uint64(d))            // mismatch maximum value
 	are.Equal(d0.Kind(), d1.Kind())     // mismatch kind
 	are.Equal(d0.String(), d1.String()) // mismatch name
 }
@@ -0,0 +1,113 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds
+
+import "math"
+
+// Distribution describes the distribution of a data size by its expected value and its variance.
+type Distribution struct {
+	Mean,
+	Variance float64
+}
+
+// Fixed returns the distribution of a size that never varies.
+func Fixed(size uint64) Distribution {
+	return Distribution{Mean: float64(size)}
+}
+
+// Uniform returns the distribution of a size uniformly distributed between min and max.
+// It is used by default for any data not implementing the Distributor interface.
+func Uniform(min, max uint64) Distribution {
+	if max <= min {
+		return Fixed(min)
+	}
+	w := float64(max) - float64(min)
+	return Distribution{
+		Mean:     float64(min) + w/2,
+		Variance: w * w / 12,
+	}
+}
+
+// Add returns the distribution of the sum of both independent sizes.
+func (d Distribution) Add(o Distribution) Distribution {
+	return Distribution{
+		Mean:     d.Mean + o.Mean,
+		Variance: d.Variance + o.Variance,
+	}
+}
+
+// Mul returns the distribution of the sum of n independent sizes following this distribution.
+func (d Distribution) Mul(n uint64) Distribution {
+	return Distribution{
+		Mean:     d.Mean * float64(n),
+		Variance: d.Variance * float64(n),
+	}
+}
+
+// Fit maps linearly the distribution of a size between fromMin and fromMax onto a size between toMin and toMax.
+// It is used when a size is derived from another one, like with a fixed overhead or a ratio.
+func (d Distribution) Fit(fromMin, fromMax, toMin, toMax uint64) Distribution {
+	switch {
+	case toMax <= toMin:
+		return Fixed(toMin)
+	case fromMax <= fromMin:
+		return Uniform(toMin, toMax)
+	}
+	k := (float64(toMax) - float64(toMin)) / (float64(fromMax) - float64(fromMin))
+	return Distribution{
+		Mean:     float64(toMin) + (d.Mean-float64(fromMin))*k,
+		Variance: d.Variance * k * k,
+	}
+}
+
+// Expected returns the expected size, saturated to Unbounded.
+func (d Distribution) Expected() uint64 {
+	return size(d.Mean)
+}
+
+// StdDev returns the standard deviation of the size, saturated to Unbounded.
+func (d Distribution) StdDev() uint64 {
+	return size(math.Sqrt(d.Variance))
+}
+
+func size(f float64) uint64 {
+	switch {
+	case f <= 0 || math.IsNaN(f):
+		return 0
+	case f >= math.MaxUint64:
+		return Unbounded
+	default:
+		return uint64(math.Round(f))
+	}
+}
+
+// Expect returns the distribution of the size of the data.
+// If the data does not implement the Distributor interface,
+// its size is assumed to be uniformly distributed between its minimum and maximum.
+func Expect(d Data) Distribution {
+	if v, ok := d.(Distributor); ok {
+		return v.Distribution()
+	}
+	return Uniform(d.Size())
+}
+
+// ExpectN returns the distribution of the size of the data for the given number of rows,
+// each one being independent. When the data implements the Scaler interface, any additional size
+// is considered as an overhead uniformly distributed, otherwise the distribution is fitted to the scaled sizes.
+func ExpectN(d Data, rows uint64) Distribution {
+	var (
+		res      = Expect(d).Mul(rows)
+		min, max = d.Size()
+		n, x     = Scale(d, rows)
+	)
+	min, max = Mul(min, rows), Mul(max, rows)
+	if n == min && x == max {
+		return res
+	}
+	if n >= min && x >= max && max < Unbounded {
+		return res.Add(Uniform(n-min, x-max))
+	}
+	return res.Fit(min, max, n, x)
+}
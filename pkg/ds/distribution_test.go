@@ -0,0 +1,126 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	ds_mock "github.com/rvflash/ds/testdata/mock/ds"
+)
+
+func TestUniform(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			min, max       uint64
+			mean, variance float64
+		}{
+			"Default":  {},
+			"Fixed":    {min: 4, max: 4, mean: 4},
+			"Inverted": {min: 8, max: 4, mean: 8},
+			"OK":       {min: 1, max: 13, mean: 7, variance: 12},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			d := ds.Uniform(tt.min, tt.max)
+			are.Equal(tt.mean, d.Mean)         // mismatch mean
+			are.Equal(tt.variance, d.Variance) // mismatch variance
+		})
+	}
+}
+
+func TestDistribution_Add(t *testing.T) {
+	are := is.New(t)
+	d := ds.Uniform(1, 13).Add(ds.Fixed(3))
+	are.Equal(ds.Distribution{Mean: 10, Variance: 12}, d) // mismatch sum
+	d = d.Mul(10)
+	are.Equal(ds.Distribution{Mean: 100, Variance: 120}, d) // mismatch product
+}
+
+func TestDistribution_Fit(t *testing.T) {
+	var (
+		are = is.New(t)
+		d   = ds.Uniform(1, 13)
+		dt  = map[string]struct {
+			fromMin, fromMax, toMin, toMax uint64
+			out                            ds.Distribution
+		}{
+			"Default": {},
+			"Fixed":   {fromMin: 1, fromMax: 13, toMin: 5, toMax: 5, out: ds.Fixed(5)},
+			"From":    {fromMin: 1, fromMax: 1, toMin: 1, toMax: 13, out: d},
+			"Shift":   {fromMin: 1, fromMax: 13, toMin: 11, toMax: 23, out: ds.Distribution{Mean: 17, Variance: 12}},
+			"Ratio":   {fromMin: 1, fromMax: 13, toMin: 2, toMax: 26, out: ds.Distribution{Mean: 14, Variance: 48}},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			are.Equal(tt.out, d.Fit(tt.fromMin, tt.fromMax, tt.toMin, tt.toMax)) // mismatch result
+		})
+	}
+}
+
+func TestDistribution_Expected(t *testing.T) {
+	are := is.New(t)
+	are.Equal(uint64(0), ds.Distribution{Mean: -1}.Expected())                    // mismatch negative
+	are.Equal(uint64(0), ds.Distribution{Mean: math.NaN()}.Expected())            // mismatch NaN
+	are.Equal(uint64(8), ds.Distribution{Mean: 7.5}.Expected())                   // mismatch rounded
+	are.Equal(ds.Unbounded, ds.Distribution{Mean: math.MaxUint64 * 2}.Expected()) // mismatch unbounded
+	are.Equal(uint64(3), ds.Distribution{Variance: 9}.StdDev())                   // mismatch standard deviation
+	are.Equal(ds.Unbounded, ds.Distribution{Variance: math.Inf(1)}.StdDev())      // mismatch infinite
+	are.Equal(uint64(7), ds.Uniform(1, 13).Expected())                            // mismatch uniform
+}
+
+func TestExpect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	d0 := ds_mock.NewMockData(ctrl)
+	d0.EXPECT().Size().Return(uint64(1), uint64(13)).AnyTimes()
+
+	type distributor struct {
+		*ds_mock.MockData
+		*ds_mock.MockDistributor
+	}
+	d1 := ds_mock.NewMockDistributor(ctrl)
+	d1.EXPECT().Distribution().Return(ds.Fixed(5)).AnyTimes()
+
+	are := is.New(t)
+	are.Equal(ds.Uniform(1, 13), ds.Expect(d0))                                               // mismatch default
+	are.Equal(ds.Fixed(5), ds.Expect(distributor{MockData: d0, MockDistributor: d1}))         // mismatch distributor
+	are.Equal(ds.Distribution{Mean: 17, Variance: 12}, ds.Expect(ds.NewDataSize(d0, 11, 23))) // mismatch overloaded
+	are.Equal(ds.Distribution{Mean: 70, Variance: 120}, ds.ExpectN(d0, 10))                   // mismatch rows
+	are.Equal(ds.Fixed(50), ds.ExpectN(distributor{MockData: d0, MockDistributor: d1}, 10))   // mismatch fixed rows
+}
+
+func TestExpectN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	d0 := ds_mock.NewMockData(ctrl)
+	d0.EXPECT().Size().Return(uint64(1), uint64(13)).AnyTimes()
+
+	type scaler struct {
+		*ds_mock.MockData
+		*ds_mock.MockScaler
+	}
+	s0 := ds_mock.NewMockScaler(ctrl)
+	s0.EXPECT().Scale(uint64(10)).Return(uint64(20), uint64(140)).AnyTimes()
+	s1 := ds_mock.NewMockScaler(ctrl)
+	s1.EXPECT().Scale(uint64(10)).Return(uint64(5), uint64(65)).AnyTimes()
+
+	are := is.New(t)
+	are.Equal(ds.Distribution{Mean: 70, Variance: 120}, ds.ExpectN(d0, 10)) // mismatch default
+	// With an overhead between 10 and 10.
+	are.Equal(ds.Distribution{Mean: 80, Variance: 120}, ds.ExpectN(scaler{MockData: d0, MockScaler: s0}, 10)) // mismatch overhead
+	// With the half of the rows.
+	are.Equal(ds.Distribution{Mean: 35, Variance: 30}, ds.ExpectN(scaler{MockData: d0, MockScaler: s1}, 10)) // mismatch fitted
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds
+
+// Children returns the children of the data, if it implements the Node interface.
+func Children(d Data) []Data {
+	if v, ok := d.(Node); ok {
+		return v.Children()
+	}
+	return nil
+}
+
+// Details returns the details of the data, if it implements the Detailer interface.
+func Details(d Data) []Data {
+	if v, ok := d.(Detailer); ok {
+		return v.Details()
+	}
+	return nil
+}
+
+// Flagged returns true if the data implements the Flagger interface and is flagged.
+func Flagged(d Data) bool {
+	if v, ok := d.(Flagger); ok {
+		return v.Flagged()
+	}
+	return false
+}
+
+// Property returns the value of the named property of the data, if it implements the Describer interface.
+func Property(d Data, name string) string {
+	if v, ok := d.(Describer); ok {
+		return v.Property(name)
+	}
+	return ""
+}
+
+// Groups returns the groups of the data, if it implements the Grouper interface.
+func Groups(d Data) []Node {
+	if v, ok := d.(Grouper); ok {
+		return v.Groups()
+	}
+	return nil
+}
+
+// NewGroup returns a node with the name, the kind and the sizes of the data, composed of these children.
+func NewGroup(d Data, children ...Data) Node {
+	return &group{Data: d, children: children}
+}
+
+type group struct {
+	Data
+	children []Data
+}
+
+// Children implements the Node interface.
+func (g group) Children() []Data {
+	return g.children
+}
+
+// Distribution implements the Distributor interface.
+func (g group) Distribution() Distribution {
+	return Expect(g.Data)
+}
+
+// Scale implements the Scaler interface.
+func (g group) Scale(rows uint64) (min, max uint64) {
+	return Scale(g.Data, rows)
+}
+
+// Property implements the Describer interface.
+func (g group) Property(name string) string {
+	return Property(g.Data, name)
+}
+
+// Flagged implements the Flagger interface.
+// A group is flagged if one of its children is flagged.
+func (g group) Flagged() bool {
+	for _, c := range g.children {
+		if Flagged(c) {
+			return true
+		}
+	}
+	return Flagged(g.Data)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	ds_mock "github.com/rvflash/ds/testdata/mock/ds"
+)
+
+// item is a data with details, that can be flagged.
+type item struct {
+	name    string
+	flagged bool
+	details []ds.Data
+}
+
+func (i item) Size() (min, max uint64) { return 1, 2 }
+func (i item) Kind() string            { return "item" }
+func (i item) String() string          { return i.name }
+func (i item) Flagged() bool           { return i.flagged }
+func (i item) Details() []ds.Data      { return i.details }
+
+func TestChildren(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		are = is.New(t)
+		d0  = ds_mock.NewMockData(ctrl)
+		d1  = ds_mock.NewMockNode(ctrl)
+	)
+	d1.EXPECT().Children().Return([]ds.Data{d0}).Times(1)
+	are.Equal(nil, ds.Children(d0))              // unexpected children
+	are.Equal([]ds.Data{d0}, ds.Children(d1))    // mismatch children
+	are.Equal(nil, ds.Children(item{name: "a"})) // unexpected children
+}
+
+func TestDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		are = is.New(t)
+		d0  = ds_mock.NewMockData(ctrl)
+		d1  = item{name: "a", details: []ds.Data{d0}}
+	)
+	are.Equal(nil, ds.Details(d0))           // unexpected details
+	are.Equal([]ds.Data{d0}, ds.Details(d1)) // mismatch details
+}
+
+func TestFlagged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		are = is.New(t)
+		d0  = ds_mock.NewMockData(ctrl)
+		d1  = item{name: "a", flagged: true}
+	)
+	are.True(!ds.Flagged(d0))                       // unexpected flag
+	are.True(!ds.Flagged(item{name: "b"}))          // unexpected flag
+	are.True(ds.Flagged(d1))                        // expected flag
+	are.True(ds.Flagged(ds.NewDataSize(d1, 3, 4)))  // expected flag on the overloaded data
+	are.True(!ds.Flagged(ds.NewDataSize(d0, 3, 4))) // unexpected flag on the overloaded data
+}
+
+func TestNewGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		are = is.New(t)
+		d0  = ds_mock.NewMockData(ctrl)
+		d1  = item{name: "a", flagged: true}
+	)
+	d0.EXPECT().Size().Return(uint64(2), uint64(6)).AnyTimes()
+	d0.EXPECT().String().Return("data").AnyTimes()
+
+	g := ds.NewGroup(d0, d1)
+	min, max := ds.Scale(g, 10)
+	are.Equal("data", g.String())             // mismatch name
+	are.Equal([]ds.Data{d1}, g.Children())    // mismatch children
+	are.Equal(uint64(20), min)                // mismatch minimum size
+	are.Equal(uint64(60), max)                // mismatch maximum size
+	are.Equal(ds.Uniform(2, 6), ds.Expect(g)) // mismatch distribution
+	are.True(ds.Flagged(g))                   // expected flag of the child
+	are.True(!ds.Flagged(ds.NewGroup(d0)))    // unexpected flag
+	are.Equal(nil, ds.Groups(d0))             // unexpected groups
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseSize parses a human size, like 1.5GB, 200 MiB, 8126B or 3T, into a number of bytes.
+// The units are the decimal and the binary ones, regardless of the case. Without unit, the size is in bytes.
+// The trailing B of the unit can be omitted, and a fractional number of bytes is truncated.
+func ParseSize(s string) (Unit, error) {
+	var (
+		v   = strings.TrimSpace(s)
+		pos = strings.IndexFunc(v, func(r rune) bool {
+			return !unicode.IsDigit(r) && r != '.'
+		})
+		num, sym = v, ""
+	)
+	if pos >= 0 {
+		num, sym = v[:pos], strings.TrimSpace(v[pos:])
+	}
+	if !number(num) {
+		return 0, WrapErr("size "+strconv.Quote(s), ErrInvalid)
+	}
+	u, err := sizeUnit(sym)
+	if err != nil {
+		return 0, WrapErr("size "+strconv.Quote(s), err)
+	}
+	r, _ := new(big.Rat).SetString(num)
+	r.Mul(r, new(big.Rat).SetUint64(uint64(u)))
+	i := new(big.Int).Quo(r.Num(), r.Denom())
+	if !i.IsUint64() {
+		return 0, WrapErr("size "+strconv.Quote(s)+" out of range", ErrInvalid)
+	}
+	return Unit(i.Uint64()), nil
+}
+
+// number returns true if the string is a positive decimal number, like 12 or 1.5.
+func number(s string) bool {
+	if s == "" || s == "." || strings.Count(s, ".") > 1 {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// sizeUnit returns the unit of a size, the byte by default, with or without its trailing B, like GiB or G.
+func sizeUnit(s string) (Unit, error) {
+	if s == "" {
+		return Byte, nil
+	}
+	u, err := ParseUnit(s)
+	if err == nil {
+		return u, nil
+	}
+	return ParseUnit(s + Byte.Symbol())
+}
+
+// Set implements the flag.Value interface.
+func (u *Unit) Set(s string) error {
+	v, err := ParseSize(s)
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (u *Unit) UnmarshalText(text []byte) error {
+	return u.Set(string(text))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The size is either a number of bytes or a human size as string, like "1.5GB".
+func (u *Unit) UnmarshalJSON(b []byte) error {
+	if bytes.HasPrefix(b, []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		return u.Set(s)
+	}
+	var i uint64
+	if err := json.Unmarshal(b, &i); err != nil {
+		return WrapErr("size "+string(b), ErrInvalid)
+	}
+	*u = Unit(i)
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds_test
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+func TestParseSize(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			out ds.Unit
+			err error
+		}{
+			"":                      {err: ds.ErrInvalid},
+			"GB":                    {err: ds.ErrInvalid},
+			"1.2.3KB":               {err: ds.ErrInvalid},
+			"-1KB":                  {err: ds.ErrInvalid},
+			"1e3":                   {err: ds.ErrInvalid},
+			"12 EB":                 {err: ds.ErrInvalid},
+			"18446744073709551616":  {err: ds.ErrInvalid},
+			"20000 PB":              {err: ds.ErrInvalid},
+			"0":                     {},
+			"8126B":                 {out: 8126},
+			"8126":                  {out: 8126},
+			" 1.5GB ":               {out: 1500000000},
+			"200 MiB":               {out: 200 * ds.MebiByte},
+			"3T":                    {out: 3 * ds.TeraByte},
+			"2ki":                   {out: 2048},
+			"0.3KiB":                {out: 307},
+			".5kb":                  {out: 500},
+			"18446744073709551615B": {out: ds.Unit(ds.Unbounded)},
+		}
+	)
+	for in, tt := range dt {
+		in, tt := in, tt
+		t.Run(in, func(t *testing.T) {
+			out, err := ds.ParseSize(in)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(tt.out, out)           // mismatch size
+		})
+	}
+}
+
+func TestUnit_Set(t *testing.T) {
+	var (
+		are = is.New(t)
+		u   ds.Unit
+		fs  = flag.NewFlagSet("test", flag.ContinueOnError)
+	)
+	var _ flag.Value = &u
+	var _ encoding.TextUnmarshaler = &u
+	fs.Var(&u, "size", "size")
+	are.NoErr(fs.Parse([]string{"-size", "1.5GiB"}))  // unexpected flag error
+	are.Equal(ds.Unit(1610612736), u)                 // mismatch flag value
+	are.Equal("1610612736", u.String())               // mismatch string value
+	are.True(errors.Is(u.Set("1 EB"), ds.ErrInvalid)) // expected invalid size
+}
+
+func TestUnit_UnmarshalJSON(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			out ds.Unit
+			err bool
+		}{
+			`{"size":1024}`:     {out: 1024},
+			`{"size":"2 KiB"}`:  {out: 2048},
+			`{"size":"2 XB"}`:   {err: true},
+			`{"size":-1}`:       {err: true},
+			`{"size":true}`:     {err: true},
+			`{"size":"1e3 KB"}`: {err: true},
+		}
+	)
+	for in, tt := range dt {
+		in, tt := in, tt
+		t.Run(in, func(t *testing.T) {
+			var v struct{ Size ds.Unit }
+			err := json.Unmarshal([]byte(in), &v)
+			are.Equal(tt.err, err != nil) // mismatch error
+			are.Equal(tt.out, v.Size)     // mismatch size
+		})
+	}
+}
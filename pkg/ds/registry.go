@@ -0,0 +1,186 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Help is the name of the subcommand printing the available estimators or the usage of one of them.
+const Help = "help"
+
+// NewRegistry returns a registry with these estimators.
+func NewRegistry(list ...Estimator) (*Registry, error) {
+	r := &Registry{estimators: make(map[string]Estimator)}
+	for _, e := range list {
+		err := r.Register(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Registry lists the estimators by their names, to run them as subcommands.
+type Registry struct {
+	estimators map[string]Estimator
+}
+
+// Register adds the estimator to the registry.
+// It fails if its name is empty, reserved or already registered.
+func (r *Registry) Register(e Estimator) error {
+	if e == nil || e.Name() == "" {
+		return WrapErr("estimator name", ErrMissing)
+	}
+	if _, ok := r.estimators[e.Name()]; ok || e.Name() == Help {
+		return WrapErr("estimator "+e.Name(), ErrInvalid)
+	}
+	r.estimators[e.Name()] = e
+	return nil
+}
+
+// Lookup returns the estimator registered with this name, if any.
+func (r *Registry) Lookup(name string) (Estimator, bool) {
+	e, ok := r.estimators[name]
+	return e, ok
+}
+
+// Names returns the names of the registered estimators, sorted in ascending order.
+func (r *Registry) Names() []string {
+	res := make([]string, 0, len(r.estimators))
+	for name := range r.estimators {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Run runs the estimator named by the first argument, with the others as its flags,
+// followed by the optional path of the file to estimate, the reader being used otherwise.
+// The help subcommand prints the available estimators, or the usage of the named one.
+// A Watcher estimator watching the files receives all the paths following the flags instead.
+//
+// The name can be preceded by the -config flag, with the path of the configuration file.
+// By default, the one in the EnvConfig environment variable is used, or the ConfigFile found
+// from the working directory upward. If the estimator is Configurable, its settings are read from this file,
+// then from its flags and finally from the environment variables.
+func (r *Registry) Run(args []string, in io.Reader, out io.Writer) error {
+	gs := flag.NewFlagSet("ds", flag.ContinueOnError)
+	config := gs.String(configFlag, os.Getenv(EnvConfig), configUsage)
+	err := gs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	args = gs.Args()
+	if len(args) == 0 {
+		return WrapErr("command", ErrMissing)
+	}
+	if args[0] == Help {
+		return r.Help(out, args[1:]...)
+	}
+	e, ok := r.Lookup(args[0])
+	if !ok {
+		return WrapErr(fmt.Sprintf("command %q", args[0]), ErrInvalid)
+	}
+	fs := r.flagSet(e)
+	c, ok := e.(Configurable)
+	if ok {
+		err = readConfigFile(*config, c, e.Name())
+		if err != nil {
+			return err
+		}
+	}
+	err = fs.Parse(args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if ok {
+		err = readEnv(c, e.Name())
+		if err != nil {
+			return err
+		}
+	}
+	if w, ok := e.(Watcher); ok && w.Watching() {
+		return w.Watch(fs.Args(), out)
+	}
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+	return e.Run(in, out)
+}
+
+// Help prints the available estimators with their description.
+// With a name, it prints the usage of this estimator with its flags.
+func (r *Registry) Help(w io.Writer, names ...string) error {
+	if len(names) == 0 {
+		_, err := fmt.Fprintln(w, "available sub commands:")
+		if err != nil {
+			return err
+		}
+		for _, name := range r.Names() {
+			_, err = fmt.Fprintf(w, "    - %s: %s\n", name, r.estimators[name].Usage())
+			if err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintf(w, "flags:\n    -%s: %s\n", configFlag, configUsage)
+		return err
+	}
+	e, ok := r.Lookup(names[0])
+	if !ok {
+		return WrapErr(fmt.Sprintf("command %q", names[0]), ErrInvalid)
+	}
+	fs := r.flagSet(e)
+	fs.SetOutput(w)
+	fs.Usage()
+	return nil
+}
+
+const (
+	configFlag  = "config"
+	configUsage = "path of the configuration file, by default the " + ConfigFile + " file found from the working directory upward"
+)
+
+// readConfigFile reads the settings of the estimator in the configuration file, if any.
+// Without path, the ConfigFile is searched from the working directory upward.
+func readConfigFile(path string, e Configurable, name string) error {
+	if path == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if path, ok = FindConfig(dir); !ok {
+			return nil
+		}
+	}
+	return readConfig(path, e, name)
+}
+
+func (r *Registry) flagSet(e Estimator) *flag.FlagSet {
+	fs := flag.NewFlagSet(e.Name(), flag.ContinueOnError)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(fs.Output(), "%s\n\nusage: ds %s [flags] [file]\n", e.Usage(), e.Name())
+		fs.PrintDefaults()
+	}
+	e.SetFlags(fs)
+	return fs
+}
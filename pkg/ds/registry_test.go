@@ -0,0 +1,139 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	ds_mock "github.com/rvflash/ds/testdata/mock/ds"
+)
+
+func newEstimator(ctrl *gomock.Controller, name string) *ds_mock.MockEstimator {
+	e := ds_mock.NewMockEstimator(ctrl)
+	e.EXPECT().Name().Return(name).AnyTimes()
+	e.EXPECT().Usage().Return("usage of " + name).AnyTimes()
+	return e
+}
+
+func TestNewRegistry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  []ds.Estimator
+			err error
+		}{
+			"Default":   {},
+			"Empty":     {in: []ds.Estimator{newEstimator(ctrl, "")}, err: ds.ErrMissing},
+			"Reserved":  {in: []ds.Estimator{newEstimator(ctrl, ds.Help)}, err: ds.ErrInvalid},
+			"Duplicate": {in: []ds.Estimator{newEstimator(ctrl, "a"), newEstimator(ctrl, "a")}, err: ds.ErrInvalid},
+			"OK":        {in: []ds.Estimator{newEstimator(ctrl, "b"), newEstimator(ctrl, "a")}},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			r, err := ds.NewRegistry(tt.in...)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if err == nil {
+				are.Equal(len(tt.in), len(r.Names())) // mismatch number of estimators
+			}
+		})
+	}
+}
+
+func TestRegistry_Help(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		are = is.New(t)
+		e   = newEstimator(ctrl, "b")
+	)
+	e.EXPECT().SetFlags(gomock.Any()).Do(func(fs *flag.FlagSet) {
+		fs.Bool("v", false, "verbose mode")
+	}).AnyTimes()
+	r, err := ds.NewRegistry(e, newEstimator(ctrl, "a"))
+	are.NoErr(err) // unexpected registry error
+
+	buf := new(bytes.Buffer)
+	are.NoErr(r.Help(buf))                                                                                           // unexpected help error
+	are.True(strings.HasPrefix(buf.String(), "available sub commands:\n    - a: usage of a\n    - b: usage of b\n")) // mismatch help
+	are.True(strings.Contains(buf.String(), "-config"))                                                              // expected config flag
+	buf.Reset()
+	are.NoErr(r.Run([]string{ds.Help, "b"}, nil, buf))              // unexpected help error
+	are.True(strings.Contains(buf.String(), "usage: ds b [flags]")) // expected usage
+	are.True(strings.Contains(buf.String(), "verbose mode"))        // expected flags
+	are.True(errors.Is(r.Help(buf, "c"), ds.ErrInvalid))            // expected unknown estimator
+}
+
+func TestRegistry_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		are     = is.New(t)
+		in      = strings.NewReader("in")
+		verbose bool
+		e       = newEstimator(ctrl, "a")
+	)
+	e.EXPECT().SetFlags(gomock.Any()).Do(func(fs *flag.FlagSet) {
+		fs.BoolVar(&verbose, "v", false, "verbose mode")
+	}).AnyTimes()
+	e.EXPECT().Run(in, gomock.Any()).DoAndReturn(func(r io.Reader, w io.Writer) error {
+		_, err := io.Copy(w, r)
+		return err
+	}).Times(1)
+	r, err := ds.NewRegistry(e)
+	are.NoErr(err) // unexpected registry error
+
+	buf := new(bytes.Buffer)
+	are.True(errors.Is(r.Run(nil, in, buf), ds.ErrMissing))           // expected missing command
+	are.True(errors.Is(r.Run([]string{"b"}, in, buf), ds.ErrInvalid)) // expected unknown command
+	are.True(r.Run([]string{"a", "-x"}, in, buf) != nil)              // expected unknown flag
+	are.True(r.Run([]string{"a", "/not/found.sql"}, in, buf) != nil)  // expected missing file
+	are.NoErr(r.Run([]string{"a", "-v"}, in, buf))                    // unexpected run error
+	are.True(verbose)                                                 // expected verbose flag
+	are.Equal("in", buf.String())                                     // mismatch output
+}
+
+type watchingEstimator struct {
+	*ds_mock.MockEstimator
+	*ds_mock.MockWatcher
+}
+
+func TestRegistry_Run_Watch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		are   = is.New(t)
+		watch bool
+		e     = newEstimator(ctrl, "a")
+		w     = ds_mock.NewMockWatcher(ctrl)
+	)
+	e.EXPECT().SetFlags(gomock.Any()).Do(func(fs *flag.FlagSet) {
+		fs.BoolVar(&watch, "watch", false, "watch mode")
+	}).AnyTimes()
+	e.EXPECT().Run(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	w.EXPECT().Watching().DoAndReturn(func() bool { return watch }).Times(2)
+	w.EXPECT().Watch([]string{"a.sql", "sql"}, gomock.Any()).Return(nil).Times(1)
+	r, err := ds.NewRegistry(watchingEstimator{MockEstimator: e, MockWatcher: w})
+	are.NoErr(err) // unexpected registry error
+
+	buf := new(bytes.Buffer)
+	are.NoErr(r.Run([]string{"a", "--watch", "a.sql", "sql"}, nil, buf)) // unexpected watch error
+	are.NoErr(r.Run([]string{"a"}, strings.NewReader("in"), buf))        // unexpected run error
+}
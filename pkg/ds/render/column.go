@@ -0,0 +1,151 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package render
+
+import (
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Column is a column of the report, displayed after the name of the data.
+// Any column other than the built-in ones is a property of the data, see the ds.Describer interface.
+type Column string
+
+// List of built-in columns.
+const (
+	// TypeColumn is the kind of the data.
+	TypeColumn = Column("type")
+	// PerRowColumn is the sizes by row.
+	PerRowColumn = Column("row")
+	// PerNColumn is the sizes for each number of rows.
+	PerNColumn = Column("n")
+)
+
+// DefaultColumns returns the columns displayed by default.
+func DefaultColumns() []Column {
+	return []Column{TypeColumn, PerRowColumn, PerNColumn}
+}
+
+// ToColumns returns the columns based on their comma separated names, like type,row,n,charset.
+// The default ones are returned if the string is empty.
+func ToColumns(s string) ([]Column, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultColumns(), nil
+	}
+	a := strings.Split(s, ",")
+	res := make([]Column, len(a))
+	for p, v := range a {
+		res[p] = Column(strings.ToLower(strings.TrimSpace(v)))
+	}
+	err := validColumns(res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// String implements the fmt.Stringer interface.
+func (c Column) String() string {
+	return string(c)
+}
+
+func validColumns(cols []Column) error {
+	done := make(map[Column]struct{}, len(cols))
+	for _, c := range cols {
+		if c == "" {
+			return ds.WrapErr("column", ds.ErrMissing)
+		}
+		if _, ok := done[c]; ok {
+			return ds.WrapErr("column "+c.String(), ds.ErrInvalid)
+		}
+		done[c] = struct{}{}
+	}
+	return nil
+}
+
+// field is a column of the report, with the function returning its value for a data.
+// Text is true if the column is not a size.
+type field struct {
+	name  string
+	text  bool
+	value func(d ds.Data) string
+}
+
+// fields returns the fields of the report, the name of the data first.
+func (r *Renderer) fields() []field {
+	res := []field{{name: dataName, text: true, value: name}}
+	for _, c := range r.columns {
+		switch c {
+		case TypeColumn:
+			res = append(res, field{name: dataType, text: true, value: ds.Data.Kind})
+		case PerRowColumn:
+			res = append(res, r.perRow()...)
+		case PerNColumn:
+			for _, n := range r.scenarios {
+				res = append(res, r.scenario(n)...)
+			}
+		default:
+			res = append(res, property(c.String()))
+		}
+	}
+	return res
+}
+
+// name returns the name of the data, flagged if excluded from the estimation or partially estimated.
+func name(d ds.Data) string {
+	if ds.Flagged(d) {
+		return d.String() + flagged
+	}
+	return d.String()
+}
+
+func (r *Renderer) perRow() []field {
+	res := []field{
+		{name: minRow, value: func(d ds.Data) string {
+			min, _ := d.Size()
+			return r.human(min)
+		}},
+		{name: maxRow, value: func(d ds.Data) string {
+			_, max := d.Size()
+			return r.human(max)
+		}},
+	}
+	if r.expected {
+		res = append(res, field{name: expRow, value: func(d ds.Data) string {
+			return r.human(ds.Expect(d).Expected())
+		}})
+	}
+	return res
+}
+
+func (r *Renderer) scenario(rows uint64) []field {
+	res := []field{
+		{name: xRow(rows, minSize), value: func(d ds.Data) string {
+			min, _ := ds.Scale(d, rows)
+			return r.human(min)
+		}},
+		{name: xRow(rows, maxSize), value: func(d ds.Data) string {
+			_, max := ds.Scale(d, rows)
+			return r.human(max)
+		}},
+	}
+	if r.expected {
+		res = append(res, field{name: xRow(rows, expSize), value: func(d ds.Data) string {
+			return r.expect(ds.ExpectN(d, rows))
+		}})
+	}
+	return res
+}
+
+func property(name string) field {
+	return field{
+		name: strings.ToUpper(name[:1]) + name[1:],
+		text: true,
+		value: func(d ds.Data) string {
+			return ds.Property(d, name)
+		},
+	}
+}
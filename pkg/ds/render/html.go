@@ -0,0 +1,157 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package render
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// section is a collapsible part of the HTML report, like a database or a table.
+// Width is the width of its bar, as a percentage of the largest one of its siblings.
+type section struct {
+	Kind     string
+	Cells    []string
+	Flagged  bool
+	Width    float64
+	Children []section
+	Details  [][]string
+}
+
+// htmlReport is the data of the HTML report.
+// Text is the CSS selector of the columns to align on the left.
+type htmlReport struct {
+	Header []string
+	Text   template.CSS
+	Roots  []section
+}
+
+// html prints the data as a self-contained HTML document, with a collapsible section
+// by root data and by child, and a bar chart of the maximum sizes for N rows of the children.
+func (r *Renderer) html(w io.Writer, data []ds.Data) error {
+	t, err := template.New("report").Parse(htmlTemplate)
+	if err != nil {
+		return err
+	}
+	res := htmlReport{Header: r.header(), Text: textSelector(r.text()), Roots: make([]section, len(data))}
+	for p, d := range data {
+		res.Roots[p] = r.section(d, ds.Unbounded)
+	}
+	return t.Execute(w, res)
+}
+
+// section returns the section of the data, with the width of its bar based on the largest size.
+func (r *Renderer) section(d ds.Data, largest uint64) section {
+	_, x := ds.Scale(d, r.perN)
+	res := section{Kind: d.Kind(), Cells: r.cells(d), Flagged: ds.Flagged(d)}
+	if largest > 0 && largest != ds.Unbounded && x != ds.Unbounded {
+		res.Width = float64(x) * 100 / float64(largest)
+	}
+	var (
+		children = ds.Children(d)
+		max      uint64
+	)
+	for _, c := range children {
+		if _, x = ds.Scale(c, r.perN); x > max {
+			max = x
+		}
+	}
+	for _, c := range children {
+		res.Children = append(res.Children, r.section(c, max))
+	}
+	for _, c := range ds.Details(d) {
+		res.Details = append(res.Details, r.cells(c))
+	}
+	return res
+}
+
+// textSelector returns the CSS selector of the text columns, like the name and the type of the data.
+func textSelector(text []bool) template.CSS {
+	var a []string
+	for p, ok := range text {
+		if ok {
+			a = append(a, strconv.Itoa(p+1))
+		}
+	}
+	if len(a) == 0 {
+		return ""
+	}
+	if a[len(a)-1] == strconv.Itoa(len(a)) {
+		// The first columns.
+		a = []string{"-n+" + strconv.Itoa(len(a))}
+	}
+	var res []string
+	for _, s := range a {
+		res = append(res, "th:nth-child("+s+"), td:nth-child("+s+")")
+	}
+	return template.CSS(strings.Join(res, ", "))
+}
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Data size estimation</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 14px; color: #24292e; margin: 2em; }
+summary { cursor: pointer; padding: .4em 0; font-weight: 600; }
+details details { margin-left: 1.5em; }
+table { border-collapse: collapse; margin: .5em 0 1em; }
+th, td { border: 1px solid #dfe2e5; padding: .3em .7em; text-align: right; white-space: nowrap; }
+th { background: #f6f8fa; }
+{{- with .Text}}
+{{.}} { text-align: left; }
+{{- end}}
+tr.total td { font-weight: 600; }
+tr.detail td { color: #6a737d; }
+.flagged { color: #cb2431; }
+.chart { width: 200px; }
+.bar { height: .8em; background: #0366d6; }
+</style>
+</head>
+<body>
+{{- range .Roots}}
+<details open>
+<summary{{if .Flagged}} class="flagged"{{end}}>{{index .Cells 0}} ({{.Kind}})</summary>
+<table>
+<thead><tr>{{range $.Header}}<th>{{.}}</th>{{end}}<th></th></tr></thead>
+<tbody>
+{{- range .Children}}
+<tr{{if .Flagged}} class="flagged"{{end}}>{{range .Cells}}<td>{{.}}</td>{{end}}<td class="chart"><div class="bar" style="width: {{printf "%.1f" .Width}}%"></div></td></tr>
+{{- end}}
+<tr class="total">{{range .Cells}}<td>{{.}}</td>{{end}}<td></td></tr>
+{{- range .Details}}
+<tr class="detail">{{range .}}<td>{{.}}</td>{{end}}<td></td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- range .Children}}
+{{- if or .Children .Details}}
+<details>
+<summary{{if .Flagged}} class="flagged"{{end}}>{{index .Cells 0}} ({{.Kind}})</summary>
+<table>
+<thead><tr>{{range $.Header}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Children}}
+<tr{{if .Flagged}} class="flagged"{{end}}>{{range .Cells}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+<tr class="total">{{range .Cells}}<td>{{.}}</td>{{end}}</tr>
+{{- range .Details}}
+<tr class="detail">{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+</details>
+{{- end}}
+{{- end}}
+</details>
+{{- end}}
+</body>
+</html>
+`
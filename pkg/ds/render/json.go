@@ -0,0 +1,131 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package render
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// size is a size in bytes, encoded as null in JSON when unbounded.
+type size uint64
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s size) MarshalJSON() ([]byte, error) {
+	if uint64(s) == ds.Unbounded {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.FormatUint(uint64(s), base10)), nil
+}
+
+// sizes are the sizes of a data.
+type sizes struct {
+	Min      size  `json:"min"`
+	Max      size  `json:"max"`
+	Expected *size `json:"expected,omitempty"`
+	StdDev   *size `json:"std_dev,omitempty"`
+}
+
+// scenario are the sizes of a data for a number of rows.
+type scenario struct {
+	Rows uint64 `json:"rows"`
+	sizes
+}
+
+// node is the JSON representation of a data, with its children and details.
+// The sizes of each scenario are only listed if there are several of them, the first one being the per_n sizes.
+// Properties are the ones requested as columns.
+type node struct {
+	Name       string            `json:"name"`
+	Kind       string            `json:"kind"`
+	Flagged    bool              `json:"flagged,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+	PerRow     sizes             `json:"per_row"`
+	Rows       uint64            `json:"rows"`
+	PerN       sizes             `json:"per_n"`
+	Scenarios  []scenario        `json:"scenarios,omitempty"`
+	Children   []node            `json:"children,omitempty"`
+	Details    []node            `json:"details,omitempty"`
+}
+
+// json prints the tree of data as JSON.
+func (r *Renderer) json(w io.Writer, data []ds.Data) error {
+	res := make([]node, len(data))
+	for p, d := range data {
+		res[p] = r.node(d, true)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+func (r *Renderer) node(d ds.Data, root bool) node {
+	var (
+		min, max = d.Size()
+		res      = node{
+			Name:       d.String(),
+			Kind:       d.Kind(),
+			Flagged:    ds.Flagged(d),
+			Properties: r.properties(d),
+			PerRow:     sizes{Min: size(min), Max: size(max)},
+			Rows:       r.perN,
+			PerN:       r.scaled(d, r.perN),
+		}
+	)
+	if r.expected {
+		e := size(ds.Expect(d).Expected())
+		res.PerRow.Expected = &e
+	}
+	if len(r.scenarios) > 1 {
+		for _, n := range r.scenarios {
+			res.Scenarios = append(res.Scenarios, scenario{Rows: n, sizes: r.scaled(d, n)})
+		}
+	}
+	if r.expanded(d, root) {
+		for _, c := range ds.Children(d) {
+			res.Children = append(res.Children, r.node(c, false))
+		}
+	}
+	for _, c := range ds.Details(d) {
+		res.Details = append(res.Details, r.node(c, false))
+	}
+	return res
+}
+
+// scaled returns the sizes of the data for this number of rows.
+func (r *Renderer) scaled(d ds.Data, rows uint64) sizes {
+	n, x := ds.Scale(d, rows)
+	res := sizes{Min: size(n), Max: size(x)}
+	if r.expected {
+		var (
+			v  = ds.ExpectN(d, rows)
+			en = size(v.Expected())
+			sn = size(v.StdDev())
+		)
+		res.Expected, res.StdDev = &en, &sn
+	}
+	return res
+}
+
+// properties returns the properties of the data requested as columns, if any.
+func (r *Renderer) properties(d ds.Data) map[string]string {
+	var res map[string]string
+	for _, c := range r.columns {
+		switch c {
+		case TypeColumn, PerRowColumn, PerNColumn:
+			continue
+		}
+		if v := ds.Property(d, c.String()); v != "" {
+			if res == nil {
+				res = make(map[string]string)
+			}
+			res[c.String()] = v
+		}
+	}
+	return res
+}
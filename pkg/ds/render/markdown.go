@@ -0,0 +1,53 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package render
+
+import (
+	"io"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Markdown alignments of the columns.
+const (
+	mdLeft  = ":---"
+	mdRight = "---:"
+)
+
+// markdown prints the rows as a GitHub flavored Markdown table. The blank rows are skipped.
+func (r *Renderer) markdown(w io.Writer, rows []ds.Data) error {
+	var (
+		buf    strings.Builder
+		header = r.header()
+		align  = make([]string, len(header))
+	)
+	for p, ok := range r.text() {
+		if ok {
+			align[p] = mdLeft
+		} else {
+			align[p] = mdRight
+		}
+	}
+	mdRow(&buf, header)
+	mdRow(&buf, align)
+	for _, d := range rows {
+		if d != nil {
+			mdRow(&buf, r.cells(d))
+		}
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+func mdRow(buf *strings.Builder, cells []string) {
+	buf.WriteString("|")
+	for _, c := range cells {
+		buf.WriteString(" ")
+		buf.WriteString(strings.ReplaceAll(c, "|", `\|`))
+		buf.WriteString(" |")
+	}
+	buf.WriteString("\n")
+}
@@ -0,0 +1,382 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package render provides methods to render data size estimations, as ASCII table or tree, CSV, JSON, Markdown or HTML.
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Format is an output format.
+type Format string
+
+// List of supported output formats.
+const (
+	TableFormat    = Format("table")
+	TreeFormat     = Format("tree")
+	CSVFormat      = Format("csv")
+	JSONFormat     = Format("json")
+	MarkdownFormat = Format("markdown")
+	HTMLFormat     = Format("html")
+)
+
+// ToFormat returns the output format based on the given name, the table one by default.
+func ToFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "":
+		return TableFormat, nil
+	case TableFormat, TreeFormat, CSVFormat, JSONFormat, MarkdownFormat, HTMLFormat:
+		return f, nil
+	default:
+		return "", ds.WrapErr("format "+s, ds.ErrInvalid)
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (f Format) String() string {
+	return string(f)
+}
+
+// Default values used to configure the renderer.
+const (
+	DefaultPerN      = 100
+	DefaultPrecision = 2
+)
+
+// Configurator is implemented by any method exposing cursor to adjust the renderer.
+type Configurator func(*Renderer) error
+
+// SetFormat defines the output format.
+func SetFormat(f Format) Configurator {
+	return func(r *Renderer) error {
+		switch f {
+		case TableFormat, TreeFormat, CSVFormat, JSONFormat, MarkdownFormat, HTMLFormat:
+			r.format = f
+			return nil
+		default:
+			return ds.WrapErr("format", ds.ErrInvalid)
+		}
+	}
+}
+
+// SetPerN defines the number of rows to take account in the estimation.
+func SetPerN(i uint64) Configurator {
+	return func(r *Renderer) error {
+		if i == 0 {
+			return ds.WrapErr("per N value", ds.ErrMissing)
+		}
+		r.perN = i
+		r.scenarios = []uint64{i}
+		return nil
+	}
+}
+
+// SetScenarios defines the numbers of rows to take account in the estimation, each one with its own sizes,
+// like the current one, the one expected next year and the one at full scale.
+// The first one is used by the outputs with a single number of rows, like the tree.
+func SetScenarios(rows ...uint64) Configurator {
+	return func(r *Renderer) error {
+		if len(rows) == 0 {
+			return ds.WrapErr("scenarios", ds.ErrMissing)
+		}
+		for _, i := range rows {
+			if i == 0 {
+				return ds.WrapErr("scenario value", ds.ErrMissing)
+			}
+		}
+		r.perN = rows[0]
+		r.scenarios = rows
+		return nil
+	}
+}
+
+// SetColumns defines the columns to display after the name of the data, the default ones if none.
+// The columns other than the built-in ones are properties of the data.
+func SetColumns(cols ...Column) Configurator {
+	return func(r *Renderer) error {
+		if len(cols) == 0 {
+			r.columns = DefaultColumns()
+			return nil
+		}
+		err := validColumns(cols)
+		if err != nil {
+			return err
+		}
+		r.columns = cols
+		return nil
+	}
+}
+
+// SetPrecision defines the decimal precision used to print data size.
+func SetPrecision(i uint64) Configurator {
+	return func(r *Renderer) error {
+		if i > math.MaxUint8 {
+			return ds.WrapErr("precision", ds.ErrInvalid)
+		}
+		r.precision = uint8(i)
+		return nil
+	}
+}
+
+// SetBinaryUnits defines if the sizes must be printed with the binary units defined by the IEC, like KiB,
+// instead of the decimal ones, like KB.
+func SetBinaryUnits(enabled bool) Configurator {
+	return func(r *Renderer) error {
+		r.binary = enabled
+		return nil
+	}
+}
+
+// SetUnit forces the unit used to print all the sizes, to make them comparable.
+// If zero, the most suited unit is used by size.
+func SetUnit(u ds.Unit) Configurator {
+	return func(r *Renderer) error {
+		if u != 0 && u.Symbol() == "" {
+			return ds.WrapErr("unit", ds.ErrInvalid)
+		}
+		r.unit = u
+		return nil
+	}
+}
+
+// SetTemplate defines the user-defined template to render the data, overloading the format.
+// The template is executed with a Report, and must be parsed with the Funcs functions.
+func SetTemplate(t *template.Template) Configurator {
+	return func(r *Renderer) error {
+		r.tpl = t
+		return nil
+	}
+}
+
+// SetVerbose defines if the children of each data must be rendered, not only the ones of the root data.
+func SetVerbose(verbose bool) Configurator {
+	return func(r *Renderer) error {
+		r.verbose = verbose
+		return nil
+	}
+}
+
+// SetExpected defines if the expected sizes must be rendered next to the minimum and maximum ones.
+func SetExpected(enabled bool) Configurator {
+	return func(r *Renderer) error {
+		r.expected = enabled
+		return nil
+	}
+}
+
+// New tries to instantiate a new renderer based on this configuration.
+func New(opts ...Configurator) (*Renderer, error) {
+	opts = append([]Configurator{
+		SetFormat(TableFormat),
+		SetPerN(DefaultPerN),
+		SetPrecision(DefaultPrecision),
+		SetColumns(),
+	}, opts...)
+	r := new(Renderer)
+	for _, opt := range opts {
+		err := opt(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Renderer renders a tree of data, each root being the total of its children.
+type Renderer struct {
+	format Format
+	binary,
+	expected,
+	verbose bool
+	precision uint8
+	perN      uint64
+	scenarios []uint64
+	columns   []Column
+	unit      ds.Unit
+	tpl       *template.Template
+}
+
+// Render renders the data in the writer.
+// The children of each root data are rendered before it, and the ones of other data only in verbose mode.
+// The details of a data are always rendered after it.
+func (r *Renderer) Render(w io.Writer, data ...ds.Data) error {
+	if r.perN == 0 {
+		return ds.ErrProcess
+	}
+	if r.tpl != nil {
+		return r.template(w, data)
+	}
+	switch r.format {
+	case CSVFormat:
+		return r.csv(w, r.rows(data))
+	case JSONFormat:
+		return r.json(w, data)
+	case TreeFormat:
+		return r.tree(w, data)
+	case MarkdownFormat:
+		return r.markdown(w, r.rows(data))
+	case HTMLFormat:
+		return r.html(w, data)
+	default:
+		return r.table(w, r.rows(data))
+	}
+}
+
+// rows returns the rows to render, flattening the tree of data, with the details in verbose mode.
+// A blank row is represented by nil.
+func (r *Renderer) rows(data []ds.Data) []ds.Data {
+	var res []ds.Data
+	for p, d := range data {
+		if p > 0 && r.verbose {
+			res = append(res, nil)
+		}
+		res = r.flatten(res, d, true)
+	}
+	return res
+}
+
+func (r *Renderer) flatten(res []ds.Data, d ds.Data, root bool) []ds.Data {
+	expanded := r.expanded(d, root)
+	if expanded {
+		for _, c := range ds.Children(d) {
+			res = r.flatten(res, c, false)
+		}
+	}
+	res = append(res, d)
+	if r.verbose {
+		// Without hierarchy, the details could be mistaken for other data.
+		res = append(res, ds.Details(d)...)
+	}
+	if expanded && !root {
+		res = append(res, nil)
+	}
+	return res
+}
+
+// expanded returns true if the children of the data must be rendered.
+func (r *Renderer) expanded(d ds.Data, root bool) bool {
+	return (root || r.verbose) && len(ds.Children(d)) > 0
+}
+
+// Columns names.
+const (
+	dataName = "Data"
+	dataType = "Type"
+	minRow   = "Per row (min)"
+	maxRow   = "Per row (max)"
+	expRow   = "Per row (exp)"
+	minSize  = "min"
+	maxSize  = "max"
+	expSize  = "exp"
+)
+
+func (r *Renderer) header() []string {
+	var res []string
+	for _, f := range r.fields() {
+		res = append(res, f.name)
+	}
+	return res
+}
+
+// text returns for each column of the report, true if it is not a size.
+func (r *Renderer) text() []bool {
+	var res []bool
+	for _, f := range r.fields() {
+		res = append(res, f.text)
+	}
+	return res
+}
+
+func xRow(i uint64, kind string) string {
+	return fmt.Sprintf("X %s (%s)", strconv.FormatUint(i, base10), kind)
+}
+
+const base10 = 10
+
+// flagged is appended to the name of the data excluded from the estimation or partially estimated.
+const flagged = " (!)"
+
+// cells returns the cells of the row of the data.
+func (r *Renderer) cells(d ds.Data) []string {
+	var (
+		fields = r.fields()
+		res    = make([]string, len(fields))
+	)
+	if d == nil {
+		return res
+	}
+	for p, f := range fields {
+		res[p] = f.value(d)
+	}
+	return res
+}
+
+// expect returns the expected size with its standard deviation, if any.
+func (r *Renderer) expect(d ds.Distribution) string {
+	s := r.human(d.Expected())
+	if d.StdDev() == 0 {
+		return s
+	}
+	return s + " ± " + r.human(d.StdDev())
+}
+
+// human returns the size in a human readable format.
+func (r *Renderer) human(size uint64) string {
+	return ds.SizeFormat{Decimal: r.precision, Binary: r.binary, Unit: r.unit}.Format(size)
+}
+
+// alignments returns the alignments of the columns of an ASCII table, the texts on the left, the sizes on the right.
+func alignments(text []bool) []int {
+	res := make([]int, len(text))
+	for p, ok := range text {
+		if ok {
+			res[p] = tablewriter.ALIGN_LEFT
+		} else {
+			res[p] = tablewriter.ALIGN_RIGHT
+		}
+	}
+	return res
+}
+
+// csv prints the rows using comma as the column separator.
+func (r *Renderer) csv(writer io.Writer, rows []ds.Data) error {
+	var (
+		w   = csv.NewWriter(writer)
+		err = w.Write(r.header())
+	)
+	if err != nil {
+		return err
+	}
+	for _, d := range rows {
+		err = w.Write(r.cells(d))
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// table prints the rows inside an ASCII table.
+func (r *Renderer) table(writer io.Writer, rows []ds.Data) error {
+	w := tablewriter.NewWriter(writer)
+	w.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	w.SetHeader(r.header())
+	w.SetColumnAlignment(alignments(r.text()))
+	for _, d := range rows {
+		w.Append(r.cells(d))
+	}
+	w.Render()
+	return nil
+}
@@ -0,0 +1,333 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package render_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/ds/render"
+)
+
+// node is a data with children and details, that can be flagged.
+type node struct {
+	name     string
+	min, max uint64
+	flagged  bool
+	children,
+	details []ds.Data
+}
+
+func (n node) Size() (min, max uint64) { return n.min, n.max }
+func (n node) Kind() string            { return "node" }
+func (n node) String() string          { return n.name }
+func (n node) Flagged() bool           { return n.flagged }
+func (n node) Children() []ds.Data     { return n.children }
+func (n node) Details() []ds.Data      { return n.details }
+
+// table is a node whose children are split into two groups.
+type table struct {
+	node
+}
+
+func (t table) Groups() []ds.Node {
+	return []ds.Node{
+		ds.NewGroup(node{name: "data", min: 1, max: 4}, t.children[:1]...),
+		ds.NewGroup(node{name: "index", min: 2, max: 5}, t.children[1:]...),
+	}
+}
+
+func tree() ds.Data {
+	return node{name: "db", min: 3, max: 9, children: []ds.Data{
+		node{name: "t", min: 3, max: 9, flagged: true,
+			children: []ds.Data{node{name: "c1", min: 1, max: 1}, node{name: "c2", min: 2, max: 8}},
+			details:  []ds.Data{node{name: "f", min: 4, max: 10}},
+		},
+	}}
+}
+
+func TestToFormat(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  string
+			out render.Format
+			err error
+		}{
+			"Default":  {out: render.TableFormat},
+			"CSV":      {in: "CSV", out: render.CSVFormat},
+			"JSON":     {in: "json", out: render.JSONFormat},
+			"Markdown": {in: "markdown", out: render.MarkdownFormat},
+			"HTML":     {in: "HTML", out: render.HTMLFormat},
+			"Tree":     {in: "Tree", out: render.TreeFormat},
+			"Invalid":  {in: "xml", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			out, err := render.ToFormat(tt.in)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(tt.out, out)           // mismatch format
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			opts []render.Configurator
+			err  error
+		}{
+			"Default":   {},
+			"Format":    {opts: []render.Configurator{render.SetFormat("xml")}, err: ds.ErrInvalid},
+			"PerN":      {opts: []render.Configurator{render.SetPerN(0)}, err: ds.ErrMissing},
+			"Precision": {opts: []render.Configurator{render.SetPrecision(256)}, err: ds.ErrInvalid},
+			"Unit":      {opts: []render.Configurator{render.SetUnit(7)}, err: ds.ErrInvalid},
+			"Scenarios": {opts: []render.Configurator{render.SetScenarios()}, err: ds.ErrMissing},
+			"Scenario":  {opts: []render.Configurator{render.SetScenarios(10, 0)}, err: ds.ErrMissing},
+			"Columns":   {opts: []render.Configurator{render.SetColumns(render.TypeColumn, render.TypeColumn)}, err: ds.ErrInvalid},
+			"Column":    {opts: []render.Configurator{render.SetColumns("")}, err: ds.ErrMissing},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			_, err := render.New(tt.opts...)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+		})
+	}
+}
+
+func TestRenderer_Render(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			opts []render.Configurator
+			out  string
+		}{
+			"Default": {
+				opts: []render.Configurator{render.SetFormat(render.CSVFormat), render.SetPerN(10)},
+				out: "Data,Type,Per row (min),Per row (max),X 10 (min),X 10 (max)\n" +
+					"t (!),node,3.00 B,9.00 B,30.00 B,90.00 B\n" +
+					"db,node,3.00 B,9.00 B,30.00 B,90.00 B\n",
+			},
+			"Verbose": {
+				opts: []render.Configurator{
+					render.SetFormat(render.CSVFormat), render.SetPerN(10), render.SetPrecision(0), render.SetVerbose(true),
+				},
+				out: "Data,Type,Per row (min),Per row (max),X 10 (min),X 10 (max)\n" +
+					"c1,node,1 B,1 B,10 B,10 B\n" +
+					"c2,node,2 B,8 B,20 B,80 B\n" +
+					"t (!),node,3 B,9 B,30 B,90 B\n" +
+					"f,node,4 B,10 B,40 B,100 B\n" +
+					",,,,,\n" +
+					"db,node,3 B,9 B,30 B,90 B\n",
+			},
+			"Markdown": {
+				opts: []render.Configurator{
+					render.SetFormat(render.MarkdownFormat), render.SetPerN(10), render.SetPrecision(0), render.SetVerbose(true),
+				},
+				out: "| Data | Type | Per row (min) | Per row (max) | X 10 (min) | X 10 (max) |\n" +
+					"| :--- | :--- | ---: | ---: | ---: | ---: |\n" +
+					"| c1 | node | 1 B | 1 B | 10 B | 10 B |\n" +
+					"| c2 | node | 2 B | 8 B | 20 B | 80 B |\n" +
+					"| t (!) | node | 3 B | 9 B | 30 B | 90 B |\n" +
+					"| f | node | 4 B | 10 B | 40 B | 100 B |\n" +
+					"| db | node | 3 B | 9 B | 30 B | 90 B |\n",
+			},
+			"Units": {
+				opts: []render.Configurator{
+					render.SetFormat(render.CSVFormat), render.SetPerN(1000), render.SetPrecision(1),
+					render.SetBinaryUnits(true), render.SetUnit(ds.KibiByte),
+				},
+				out: "Data,Type,Per row (min),Per row (max),X 1000 (min),X 1000 (max)\n" +
+					"t (!),node,0.0 KiB,0.0 KiB,2.9 KiB,8.7 KiB\n" +
+					"db,node,0.0 KiB,0.0 KiB,2.9 KiB,8.7 KiB\n",
+			},
+			"Scenarios": {
+				opts: []render.Configurator{
+					render.SetFormat(render.CSVFormat), render.SetScenarios(10, 1e3), render.SetPrecision(0),
+					render.SetColumns(render.PerNColumn, "color"),
+				},
+				out: "Data,X 10 (min),X 10 (max),X 1000 (min),X 1000 (max),Color\n" +
+					"t (!),30 B,90 B,3 KB,9 KB,\n" +
+					"db,30 B,90 B,3 KB,9 KB,\n",
+			},
+			"Expected": {
+				opts: []render.Configurator{
+					render.SetFormat(render.CSVFormat), render.SetPerN(10), render.SetPrecision(0), render.SetExpected(true),
+				},
+				out: "Data,Type,Per row (min),Per row (max),Per row (exp),X 10 (min),X 10 (max),X 10 (exp)\n" +
+					"t (!),node,3 B,9 B,6 B,30 B,90 B,60 B ± 5 B\n" +
+					"db,node,3 B,9 B,6 B,30 B,90 B,60 B ± 5 B\n",
+			},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			r, err := render.New(tt.opts...)
+			are.NoErr(err) // unexpected configuration error
+			buf := new(bytes.Buffer)
+			err = r.Render(buf, tree())
+			are.NoErr(err)                  // unexpected render error
+			are.Equal(tt.out, buf.String()) // mismatch output
+		})
+	}
+}
+
+func TestRenderer_Render_Table(t *testing.T) {
+	are := is.New(t)
+	r, err := render.New()
+	are.NoErr(err) // unexpected configuration error
+	buf := new(bytes.Buffer)
+	err = r.Render(buf, tree())
+	are.NoErr(err)                                       // unexpected render error
+	are.True(strings.Contains(buf.String(), "| t (!) ")) // expected flagged table
+	are.True(!strings.Contains(buf.String(), "| c1 "))   // unexpected column in non-verbose mode
+}
+
+func TestRenderer_Render_JSON(t *testing.T) {
+	are := is.New(t)
+	r, err := render.New(render.SetFormat(render.JSONFormat), render.SetPerN(10))
+	are.NoErr(err) // unexpected configuration error
+	buf := new(bytes.Buffer)
+	err = r.Render(buf, tree(), node{name: "big", max: ds.Unbounded})
+	are.NoErr(err) // unexpected render error
+
+	var out []struct {
+		Name     string
+		Flagged  bool
+		PerRow   map[string]*uint64 `json:"per_row"`
+		Children []struct {
+			Name     string
+			Flagged  bool
+			Children []interface{}
+			Details  []struct{ Name string }
+		}
+	}
+	err = json.Unmarshal(buf.Bytes(), &out)
+	are.NoErr(err)                                     // unexpected JSON error
+	are.Equal(2, len(out))                             // mismatch number of roots
+	are.Equal("db", out[0].Name)                       // mismatch root name
+	are.Equal(1, len(out[0].Children))                 // mismatch number of children
+	are.True(out[0].Children[0].Flagged)               // expected flagged child
+	are.Equal(0, len(out[0].Children[0].Children))     // unexpected grandchildren in non-verbose mode
+	are.Equal("f", out[0].Children[0].Details[0].Name) // mismatch details
+	are.Equal(nil, out[1].PerRow["max"])               // expected unbounded size as null
+}
+
+func TestRenderer_Render_Tree(t *testing.T) {
+	var (
+		are  = is.New(t)
+		root = node{name: "db", min: 3, max: 9, children: []ds.Data{
+			table{node{name: "t", min: 3, max: 9,
+				children: []ds.Data{node{name: "c1", min: 1, max: 4}, node{name: "k1", min: 2, max: 5}},
+				details:  []ds.Data{node{name: "f", min: 4, max: 10}},
+			}},
+		}}
+		dt = map[string]struct {
+			verbose bool
+			out     []string
+		}{
+			"Default": {out: []string{
+				"| db          | node | 3 B | 9 B | 30 B |  90 B |          |  100% |",
+				"| └─ t        | node | 3 B | 9 B | 30 B |  90 B |   100% |  100% |",
+				"|    ├─ data  | node | 1 B | 4 B | 10 B |  40 B |    44% |   44% |",
+				"|    ├─ index | node | 2 B | 5 B | 20 B |  50 B |    56% |   56% |",
+				"|    └─ f     | node | 4 B | 10 B | 40 B | 100 B |          |         |",
+			}},
+			"Verbose": {verbose: true, out: []string{
+				"| db          | node | 3 B | 9 B | 30 B |  90 B |          |  100% |",
+				"|    ├─ data  | node | 1 B | 4 B | 10 B |  40 B |    44% |   44% |",
+				"|    │  └─ c1 | node | 1 B | 4 B | 10 B |  40 B |   100% |   44% |",
+				"|    │  └─ k1 | node | 2 B | 5 B | 20 B |  50 B |   100% |   56% |",
+			}},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			r, err := render.New(
+				render.SetFormat(render.TreeFormat), render.SetPerN(10), render.SetPrecision(0), render.SetVerbose(tt.verbose),
+			)
+			are.NoErr(err) // unexpected configuration error
+			buf := new(bytes.Buffer)
+			are.NoErr(r.Render(buf, root)) // unexpected render error
+			out := strings.Join(strings.Fields(buf.String()), " ")
+			for _, line := range tt.out {
+				are.True(strings.Contains(out, strings.Join(strings.Fields(line), " "))) // expected row
+			}
+		})
+	}
+}
+
+func TestRenderer_Render_HTML(t *testing.T) {
+	are := is.New(t)
+	r, err := render.New(render.SetFormat(render.HTMLFormat), render.SetPerN(10))
+	are.NoErr(err) // unexpected configuration error
+	buf := new(bytes.Buffer)
+	root := node{name: "<db>", min: 4, max: 9, children: []ds.Data{
+		tree().(node).children[0],
+		node{name: "u", min: 1, max: 3},
+	}}
+	err = r.Render(buf, root)
+	are.NoErr(err) // unexpected render error
+	out := buf.String()
+	are.True(strings.HasPrefix(out, "<!DOCTYPE html>"))                      // expected HTML document
+	are.True(strings.Contains(out, "<summary>&lt;db&gt; (node)</summary>"))  // expected escaped root section
+	are.True(strings.Contains(out, `<summary class="flagged">t (!) (node)`)) // expected flagged table section
+	are.True(strings.Contains(out, `style="width: 100.0%"`))                 // expected largest bar
+	are.True(strings.Contains(out, `style="width: 33.3%"`))                  // expected relative bar
+	are.True(strings.Contains(out, `<tr class="detail"><td>f</td>`))         // expected details
+	are.True(!strings.Contains(out, "<summary>u (node)"))                    // unexpected section without children
+	are.True(!strings.Contains(out, "http"))                                 // unexpected external resource
+}
+
+func TestRenderer_Render_Template(t *testing.T) {
+	are := is.New(t)
+	tpl, err := template.New("test").Funcs(render.Funcs()).Parse(
+		`{{ .Rows }}{{ range .Data }} {{ .String }}={{ (size .).MaxN }}/{{ human (size .).MaxN }}` +
+			`{{ range children . }} {{ .String }}{{ if flagged . }}!{{ end }}{{ end }}{{ end }}`,
+	)
+	are.NoErr(err) // unexpected parse error
+	r, err := render.New(render.SetPerN(10), render.SetPrecision(0), render.SetTemplate(tpl))
+	are.NoErr(err) // unexpected renderer error
+	buf := new(bytes.Buffer)
+	err = r.Render(buf, tree())
+	are.NoErr(err)                              // unexpected render error
+	are.Equal("10 db=90/90 B t!", buf.String()) // mismatch output
+}
+
+func TestToColumns(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			out []render.Column
+			err error
+		}{
+			"":                {out: render.DefaultColumns()},
+			"n, Charset":      {out: []render.Column{render.PerNColumn, "charset"}},
+			"type,,row":       {err: ds.ErrMissing},
+			"type,row,n,type": {err: ds.ErrInvalid},
+		}
+	)
+	for in, tt := range dt {
+		tt := tt
+		t.Run(in, func(t *testing.T) {
+			out, err := render.ToColumns(in)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(tt.out, out)           // mismatch columns
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package render
+
+import (
+	"io"
+	"path/filepath"
+	"text/template"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Report is the data given to a user-defined template.
+type Report struct {
+	// Rows is the number of rows used to scale the sizes, the first of the scenarios.
+	Rows uint64
+	// Scenarios lists the numbers of rows to estimate.
+	Scenarios []uint64
+	// Data lists the data to render, like the databases with all their properties.
+	Data []ds.Data
+}
+
+// Sizes are the raw sizes of a data in bytes, by row and for a number of rows.
+// An unbounded size equals ds.Unbounded.
+type Sizes struct {
+	Min,
+	Max,
+	Expected,
+	MinN,
+	MaxN,
+	ExpectedN,
+	StdDevN uint64
+}
+
+// Funcs returns the functions available in the templates:
+//   - size returns the Sizes of a data for the number of rows of the report,
+//   - scale returns the Sizes of a data for the given number of rows, like one of the scenarios,
+//   - property returns the value of the named property of a data, like its charset,
+//   - human formats a size in bytes as the other outputs, like 1.50 KB,
+//   - children, groups and details return the data composing a data, its groups and its details,
+//   - flagged returns true if the data is excluded from the estimation or partially estimated.
+//
+// They must be added to the template before parsing it, the renderer settings being applied at the execution.
+func Funcs() template.FuncMap {
+	return new(Renderer).funcs()
+}
+
+// ParseTemplateFile parses the template file, with the functions available in the templates.
+func ParseTemplateFile(path string) (*template.Template, error) {
+	return template.New(filepath.Base(path)).Funcs(Funcs()).ParseFiles(path)
+}
+
+func (r *Renderer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"size": func(d ds.Data) Sizes {
+			return r.sizes(d, r.perN)
+		},
+		"scale":    r.sizes,
+		"human":    r.human,
+		"property": ds.Property,
+		"children": func(d ds.Data) []ds.Data {
+			return ds.Children(d)
+		},
+		"groups": func(d ds.Data) []ds.Node {
+			return ds.Groups(d)
+		},
+		"details": func(d ds.Data) []ds.Data {
+			return ds.Details(d)
+		},
+		"flagged": func(d ds.Data) bool {
+			return ds.Flagged(d)
+		},
+	}
+}
+
+func (r *Renderer) sizes(d ds.Data, rows uint64) Sizes {
+	var (
+		res Sizes
+		v   = ds.ExpectN(d, rows)
+	)
+	res.Min, res.Max = d.Size()
+	res.MinN, res.MaxN = ds.Scale(d, rows)
+	res.Expected = ds.Expect(d).Expected()
+	res.ExpectedN, res.StdDevN = v.Expected(), v.StdDev()
+	return res
+}
+
+// template executes the user-defined template with the report of the data.
+func (r *Renderer) template(w io.Writer, data []ds.Data) error {
+	t, err := r.tpl.Clone()
+	if err != nil {
+		return err
+	}
+	return t.Funcs(r.funcs()).Execute(w, Report{Rows: r.perN, Scenarios: r.scenarios, Data: data})
+}
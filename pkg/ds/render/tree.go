@@ -0,0 +1,109 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package render
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Columns names of the shares, computed on the maximum sizes for N rows.
+const (
+	parentShare = "% parent"
+	totalShare  = "% total"
+)
+
+// Tree branches.
+const (
+	branch     = "├─ "
+	lastBranch = "└─ "
+	trunk      = "│  "
+	space3     = "   "
+)
+
+// tree prints the data as a tree inside an ASCII table, each data with its share of its parent and of the total.
+// The groups of a data, like the data and the indexes of a table, are always rendered.
+func (r *Renderer) tree(writer io.Writer, data []ds.Data) error {
+	var total uint64
+	for _, d := range data {
+		_, x := ds.Scale(d, r.perN)
+		total = ds.Add(total, x)
+	}
+	var rows [][]string
+	for _, d := range data {
+		rows = r.branch(rows, d, "", "", ds.Unbounded, total, true)
+	}
+	header := append(r.header(), parentShare, totalShare)
+	w := tablewriter.NewWriter(writer)
+	w.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	w.SetHeader(header)
+	w.SetColumnAlignment(alignments(append(r.text(), false, false)))
+	w.AppendBulk(rows)
+	w.Render()
+	return nil
+}
+
+// branch appends the row of the data, then the ones of its children and details.
+func (r *Renderer) branch(rows [][]string, d ds.Data, prefix, indent string, parent, total uint64, root bool) [][]string {
+	_, x := ds.Scale(d, r.perN)
+	row := r.cells(d)
+	row[0] = prefix + row[0]
+	rows = append(rows, append(row, share(x, parent, r.precision), share(x, total, r.precision)))
+
+	var (
+		children = r.branches(d, root)
+		details  = ds.Details(d)
+		last     = len(children) + len(details) - 1
+	)
+	for p, c := range children {
+		rows = r.branch(rows, c, indent+fork(p == last), indent+bark(p == last), x, total, false)
+	}
+	for p, c := range details {
+		row = r.cells(c)
+		row[0] = indent + fork(len(children)+p == last) + row[0]
+		rows = append(rows, append(row, "", ""))
+	}
+	return rows
+}
+
+// branches returns the groups of the data if any, or its children if they must be rendered.
+func (r *Renderer) branches(d ds.Data, root bool) []ds.Data {
+	if groups := ds.Groups(d); len(groups) > 0 {
+		res := make([]ds.Data, len(groups))
+		for p, g := range groups {
+			res[p] = g
+		}
+		return res
+	}
+	if r.expanded(d, root) {
+		return ds.Children(d)
+	}
+	return nil
+}
+
+func fork(last bool) string {
+	if last {
+		return lastBranch
+	}
+	return branch
+}
+
+func bark(last bool) string {
+	if last {
+		return space3
+	}
+	return trunk
+}
+
+// share returns the size as a percentage of the total, if it can be computed.
+func share(size, total uint64, decimal uint8) string {
+	if total == 0 || total == ds.Unbounded || size == ds.Unbounded {
+		return ""
+	}
+	return fmt.Sprintf("%.*f%%", decimal, float64(size)*100/float64(total))
+}
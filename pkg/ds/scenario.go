@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ParseRows parses a positive number of rows, as an integer, like 1000000,
+// or in scientific notation, like 1e6 or 2.5e6.
+func ParseRows(s string) (uint64, error) {
+	v := strings.TrimSpace(s)
+	if v == "" || strings.HasPrefix(v, "-") || strings.HasPrefix(v, "+") {
+		return 0, WrapErr("rows "+strconv.Quote(s), ErrInvalid)
+	}
+	r, ok := new(big.Rat).SetString(v)
+	if !ok || !r.IsInt() {
+		return 0, WrapErr("rows "+strconv.Quote(s), ErrInvalid)
+	}
+	if !r.Num().IsUint64() {
+		return 0, WrapErr("rows "+strconv.Quote(s)+" out of range", ErrInvalid)
+	}
+	i := r.Num().Uint64()
+	if i == 0 {
+		return 0, WrapErr("rows "+strconv.Quote(s), ErrMissing)
+	}
+	return i, nil
+}
+
+// Scenarios lists the numbers of rows to estimate, like the current one, the one expected next year
+// and the one at full scale. It implements the flag.Value interface, the numbers being separated by commas.
+type Scenarios []uint64
+
+// Set implements the flag.Value interface.
+func (s *Scenarios) Set(v string) error {
+	var res Scenarios
+	for _, p := range strings.Split(v, ",") {
+		i, err := ParseRows(p)
+		if err != nil {
+			return err
+		}
+		res = append(res, i)
+	}
+	*s = res
+	return nil
+}
+
+// String implements the flag.Value interface.
+func (s Scenarios) String() string {
+	a := make([]string, len(s))
+	for p, i := range s {
+		a[p] = strconv.FormatUint(i, base10)
+	}
+	return strings.Join(a, ",")
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (s *Scenarios) UnmarshalText(text []byte) error {
+	return s.Set(string(text))
+}
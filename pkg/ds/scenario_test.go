@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds_test
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+func TestParseRows(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			out uint64
+			err error
+		}{
+			"":                     {err: ds.ErrInvalid},
+			"0":                    {err: ds.ErrMissing},
+			"-1":                   {err: ds.ErrInvalid},
+			"1.5":                  {err: ds.ErrInvalid},
+			"1e-3":                 {err: ds.ErrInvalid},
+			"1e20":                 {err: ds.ErrInvalid},
+			"1M":                   {err: ds.ErrInvalid},
+			"1000000":              {out: 1000000},
+			" 1e6 ":                {out: 1000000},
+			"2.5e9":                {out: 2500000000},
+			"18446744073709551615": {out: 18446744073709551615},
+		}
+	)
+	for in, tt := range dt {
+		tt := tt
+		t.Run(in, func(t *testing.T) {
+			out, err := ds.ParseRows(in)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(tt.out, out)           // mismatch rows
+		})
+	}
+}
+
+func TestScenarios_Set(t *testing.T) {
+	var (
+		are = is.New(t)
+		s   ds.Scenarios
+		fs  = flag.NewFlagSet("test", flag.ContinueOnError)
+	)
+	fs.Var(&s, "n", "")
+	err := fs.Parse([]string{"-n", "1e6,100000000,1e9"})
+	are.NoErr(err)                                             // unexpected parse error
+	are.Equal(ds.Scenarios{1000000, 100000000, 1000000000}, s) // mismatch scenarios
+	are.Equal("1000000,100000000,1000000000", s.String())      // mismatch string
+	are.True(errors.Is(s.Set("1e6,"), ds.ErrInvalid))          // expected empty scenario error
+	are.Equal(ds.Scenarios{1000000, 100000000, 1000000000}, s) // expected unchanged scenarios
+}
@@ -7,6 +7,7 @@ package ds
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // List of supported size ranges.
@@ -16,6 +17,16 @@ const (
 	MegaByte      = KiloByte * KiloByte
 	GigaByte      = MegaByte * KiloByte
 	TeraByte      = GigaByte * KiloByte
+	PetaByte      = TeraByte * KiloByte
+)
+
+// List of supported binary size ranges, as defined by the IEC.
+const (
+	KibiByte = 1024 * Byte
+	MebiByte = KibiByte * KibiByte
+	GibiByte = MebiByte * KibiByte
+	TebiByte = GibiByte * KibiByte
+	PebiByte = TebiByte * KibiByte
 )
 
 // HumanSize converts the given number of bytes into a human size in bytes.
@@ -23,23 +34,85 @@ func HumanSize(size uint64, decimal uint8) string {
 	return Unit(size).Format(decimal)
 }
 
+// FormatSize converts the given number of bytes into a human size, as HumanSize,
+// except for an Unbounded size, returned as such.
+func FormatSize(size uint64, decimal uint8) string {
+	return SizeFormat{Decimal: decimal}.Format(size)
+}
+
+const unbounded = "unbounded"
+
+// SizeFormat defines how to format a size.
+type SizeFormat struct {
+	// Decimal is the number of decimals to display.
+	Decimal uint8
+	// Binary uses the binary units defined by the IEC, like KiB, instead of the decimal ones, like KB.
+	Binary bool
+	// Unit forces the unit of any size to make them comparable, zero meaning the most suited one by size.
+	Unit Unit
+}
+
+// Format converts the given number of bytes into a human size, except for an Unbounded size, returned as such.
+func (f SizeFormat) Format(size uint64) string {
+	if size == Unbounded {
+		return unbounded
+	}
+	r := f.Unit
+	if r.Symbol() == "" {
+		r = interval(Unit(size), f.Binary)
+	}
+	return format(Unit(size), r, f.Decimal)
+}
+
+// ParseUnit returns the unit based on its symbol, like KB or GiB, regardless of the case.
+func ParseUnit(s string) (Unit, error) {
+	for _, u := range append(units(false), units(true)...) {
+		if strings.EqualFold(u.Symbol(), s) {
+			return u, nil
+		}
+	}
+	if strings.EqualFold(Byte.Symbol(), s) {
+		return Byte, nil
+	}
+	return 0, WrapErr("unit "+s, ErrInvalid)
+}
+
 // Unit is the unit of measure.
 type Unit uint64
 
 // Format returns a human size readable of the Unit.
 func (u Unit) Format(decimal uint8) string {
-	r, p := interval(u)
-	if r < KiloByte {
-		if decimal == 0 {
-			return u.String() + " " + p
-		}
-		return fmt.Sprintf("%s.%0*d %s", u.String(), decimal, 0, p)
-	}
-	i := u / r
-	if decimal == 0 {
-		return i.String() + " " + p
+	return format(u, interval(u, false), decimal)
+}
+
+// Symbol returns the symbol of the unit, or an empty string if it is not a supported one.
+func (u Unit) Symbol() string {
+	switch u {
+	case Byte:
+		return "B"
+	case KiloByte:
+		return "KB"
+	case MegaByte:
+		return "MB"
+	case GigaByte:
+		return "GB"
+	case TeraByte:
+		return "TB"
+	case PetaByte:
+		return "PB"
+	case KibiByte:
+		return "KiB"
+	case MebiByte:
+		return "MiB"
+	case GibiByte:
+		return "GiB"
+	case TebiByte:
+		return "TiB"
+	case PebiByte:
+		return "PiB"
+	default:
+		return ""
 	}
-	return fmt.Sprintf("%s.%s %s", i.String(), fractional(u-(i*r), int(decimal)), p)
 }
 
 const base10 = 10
@@ -49,40 +122,40 @@ func (u Unit) String() string {
 	return strconv.FormatUint(uint64(u), base10)
 }
 
-func interval(u Unit) (Unit, string) {
-	switch {
-	case u > TeraByte:
-		return TeraByte, "TB"
-	case u > GigaByte:
-		return GigaByte, "GB"
-	case u > MegaByte:
-		return MegaByte, "MB"
-	case u > KiloByte:
-		return KiloByte, "KB"
-	default:
-		return 1, "B"
+// format returns the size expressed in the given unit, with this number of decimals, truncated.
+func format(u, r Unit, decimal uint8) string {
+	i := u / r
+	if decimal == 0 {
+		return i.String() + " " + r.Symbol()
 	}
+	return fmt.Sprintf("%s.%s %s", i.String(), fractional(u%r, r, decimal), r.Symbol())
 }
 
-func fractional(u Unit, n int) string {
-	var size int
-	switch {
-	case u > GigaByte:
-		size = 12
-	case u > MegaByte:
-		size = 9
-	case u > KiloByte:
-		size = 6
-	default:
-		size = 3
+// interval returns the largest unit lower than the size.
+func interval(u Unit, binary bool) Unit {
+	for _, r := range units(binary) {
+		if u > r {
+			return r
+		}
+	}
+	return Byte
+}
+
+// units returns the multiple units of byte, in descending order.
+func units(binary bool) []Unit {
+	if binary {
+		return []Unit{PebiByte, TebiByte, GibiByte, MebiByte, KibiByte}
 	}
-	s := fmt.Sprintf("%0*d", size, u)
-	return s[:min(n, len(s))]
+	return []Unit{PetaByte, TeraByte, GigaByte, MegaByte, KiloByte}
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+// fractional returns the n first digits of the fractional part of u divided by r, with u lower than r.
+func fractional(u, r Unit, n uint8) string {
+	b := make([]byte, n)
+	for p := range b {
+		u *= base10
+		b[p] = '0' + byte(u/r)
+		u %= r
 	}
-	return b
+	return string(b)
 }
@@ -5,6 +5,7 @@
 package ds_test
 
 import (
+	"errors"
 	"math"
 	"testing"
 
@@ -23,7 +24,7 @@ func TestHumanSize(t *testing.T) {
 			{out: "0 B"},
 			{in: math.MaxUint16, out: "65 KB"},
 			{in: math.MaxUint32, out: "4 GB"},
-			{in: math.MaxUint64, decimal: 3, out: "18446744.073 TB"},
+			{in: math.MaxUint64, decimal: 3, out: "18446.744 PB"},
 			{in: 10240, decimal: 2, out: "10.24 KB"},
 			{in: 425005, decimal: 2, out: "425.00 KB"},
 			{in: 8741208, decimal: 2, out: "8.74 MB"},
@@ -46,3 +47,64 @@ func TestHumanSize(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatSize(t *testing.T) {
+	are := is.New(t)
+	are.Equal("unbounded", ds.FormatSize(ds.Unbounded, 2))                           // mismatch unbounded
+	are.Equal(ds.HumanSize(10240, 2), ds.FormatSize(10240, 2))                       // mismatch human size
+	are.Equal(ds.HumanSize(math.MaxUint64-1, 0), ds.FormatSize(math.MaxUint64-1, 0)) // mismatch large size
+}
+
+func TestSizeFormat_Format(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  uint64
+			f   ds.SizeFormat
+			out string
+		}{
+			"Default":   {out: "0 B"},
+			"Unbounded": {in: ds.Unbounded, f: ds.SizeFormat{Unit: ds.GibiByte}, out: "unbounded"},
+			"Remainder": {in: 1000500, f: ds.SizeFormat{Decimal: 2}, out: "1.00 MB"},
+			"Petabyte":  {in: 2500000000000000, f: ds.SizeFormat{Decimal: 1}, out: "2.5 PB"},
+			"Binary":    {in: 1536, f: ds.SizeFormat{Decimal: 2, Binary: true}, out: "1.50 KiB"},
+			"Gibibyte":  {in: 5 * uint64(ds.GibiByte), f: ds.SizeFormat{Binary: true}, out: "5 GiB"},
+			"Fixed":     {in: 10240, f: ds.SizeFormat{Decimal: 3, Unit: ds.MegaByte}, out: "0.010 MB"},
+			"FixedBin":  {in: 3 * uint64(ds.TebiByte), f: ds.SizeFormat{Unit: ds.GibiByte}, out: "3072 GiB"},
+			"Bytes":     {in: 10240, f: ds.SizeFormat{Decimal: 1, Unit: ds.Byte}, out: "10240.0 B"},
+			"Invalid":   {in: 10240, f: ds.SizeFormat{Decimal: 2, Unit: 7}, out: "10.24 KB"},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			are.Equal(tt.out, tt.f.Format(tt.in)) // mismatch result
+		})
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			out ds.Unit
+			err error
+		}{
+			"":    {err: ds.ErrInvalid},
+			"B":   {out: ds.Byte},
+			"kb":  {out: ds.KiloByte},
+			"PB":  {out: ds.PetaByte},
+			"GiB": {out: ds.GibiByte},
+			"pib": {out: ds.PebiByte},
+			"EB":  {err: ds.ErrInvalid},
+		}
+	)
+	for in, tt := range dt {
+		in, tt := in, tt
+		t.Run(in, func(t *testing.T) {
+			out, err := ds.ParseUnit(in)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(tt.out, out)           // mismatch unit
+		})
+	}
+}
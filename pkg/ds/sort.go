@@ -0,0 +1,51 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds
+
+import (
+	"sort"
+	"strings"
+)
+
+// Order defines how to sort data by size.
+type Order string
+
+// List of supported orders.
+const (
+	// Unsorted keeps the original order.
+	Unsorted = Order("")
+	// ByMinSize sorts the data by descending minimum size.
+	ByMinSize = Order("min")
+	// ByMaxSize sorts the data by descending maximum size.
+	ByMaxSize = Order("max")
+)
+
+// ToOrder returns the order based on its name, unsorted by default.
+func ToOrder(s string) (Order, error) {
+	switch o := Order(strings.ToLower(s)); o {
+	case Unsorted, ByMinSize, ByMaxSize:
+		return o, nil
+	default:
+		return Unsorted, WrapErr("order "+s, ErrInvalid)
+	}
+}
+
+// Sort sorts the data by their descending sizes for this number of rows,
+// keeping the original order of the data with the same size.
+func (o Order) Sort(data []Data, rows uint64) {
+	if o == Unsorted {
+		return
+	}
+	size := func(d Data) uint64 {
+		min, max := Scale(d, rows)
+		if o == ByMinSize {
+			return min
+		}
+		return max
+	}
+	sort.SliceStable(data, func(i, j int) bool {
+		return size(data[i]) > size(data[j])
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ds_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	ds_mock "github.com/rvflash/ds/testdata/mock/ds"
+)
+
+func TestToOrder(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			out ds.Order
+			err error
+		}{
+			"":     {out: ds.Unsorted},
+			"MIN":  {out: ds.ByMinSize},
+			"max":  {out: ds.ByMaxSize},
+			"name": {err: ds.ErrInvalid},
+		}
+	)
+	for in, tt := range dt {
+		in, tt := in, tt
+		t.Run(in, func(t *testing.T) {
+			out, err := ds.ToOrder(in)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(tt.out, out)           // mismatch order
+		})
+	}
+}
+
+func TestOrder_Sort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newData := func(name string, min, max uint64) ds.Data {
+		d := ds_mock.NewMockData(ctrl)
+		d.EXPECT().Size().Return(min, max).AnyTimes()
+		d.EXPECT().String().Return(name).AnyTimes()
+		return d
+	}
+	names := func(data []ds.Data) string {
+		var s string
+		for _, d := range data {
+			s += d.String()
+		}
+		return s
+	}
+	var (
+		are = is.New(t)
+		dt  = map[ds.Order]string{
+			ds.Unsorted:  "abcd",
+			ds.ByMinSize: "cbad",
+			ds.ByMaxSize: "dacb",
+		}
+	)
+	for in, out := range dt {
+		in, out := in, out
+		t.Run(string(in), func(t *testing.T) {
+			data := []ds.Data{newData("a", 2, 8), newData("b", 3, 4), newData("c", 5, 5), newData("d", 2, 9)}
+			in.Sort(data, 10)
+			are.Equal(out, names(data)) // mismatch order
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import "github.com/rvflash/ds/pkg/ds"
+
+// alias is a synonym of a MySQL data type, with its side effects.
+type alias struct {
+	// words is the synonym, like "double precision".
+	words []string
+	// typ is the canonical data type.
+	typ DataType
+	// length is the implicit length of the data type, if any.
+	length string
+	// charset is the implicit charset of the data type, if any.
+	charset string
+	// serial is true if the data type implies NOT NULL AUTO_INCREMENT UNIQUE.
+	serial bool
+}
+
+// nationalCharset is the charset used by the national data types.
+const nationalCharset = "utf8"
+
+// aliases returns the synonyms of the data types, the longest ones first.
+// See https://dev.mysql.com/doc/refman/8.0/en/other-vendor-data-types.html
+func aliases() []alias {
+	return []alias{
+		{words: []string{"national", "character", "varying"}, typ: VarChar, charset: nationalCharset},
+		{words: []string{"national", "char", "varying"}, typ: VarChar, charset: nationalCharset},
+		{words: []string{"national", "varchar"}, typ: VarChar, charset: nationalCharset},
+		{words: []string{"national", "character"}, typ: Char, charset: nationalCharset},
+		{words: []string{"national", "char"}, typ: Char, charset: nationalCharset},
+		{words: []string{"nchar", "varying"}, typ: VarChar, charset: nationalCharset},
+		{words: []string{"nchar", "varchar"}, typ: VarChar, charset: nationalCharset},
+		{words: []string{"character", "varying"}, typ: VarChar},
+		{words: []string{"char", "varying"}, typ: VarChar},
+		{words: []string{"double", "precision"}, typ: Double},
+		{words: []string{"long", "varbinary"}, typ: MediumBlob},
+		{words: []string{"long", "varchar"}, typ: MediumText},
+		{words: []string{"nvarchar"}, typ: VarChar, charset: nationalCharset},
+		{words: []string{"nchar"}, typ: Char, charset: nationalCharset},
+		{words: []string{"character"}, typ: Char},
+		{words: []string{"long"}, typ: MediumText},
+		{words: []string{"bool"}, typ: TinyInt, length: "1"},
+		{words: []string{"boolean"}, typ: TinyInt, length: "1"},
+		{words: []string{"serial"}, typ: BigInt, serial: true},
+		{words: []string{"int1"}, typ: TinyInt},
+		{words: []string{"int2"}, typ: SmallInt},
+		{words: []string{"int3"}, typ: MediumInt},
+		{words: []string{"middleint"}, typ: MediumInt},
+		{words: []string{"int4"}, typ: Int},
+		{words: []string{"int8"}, typ: BigInt},
+		{words: []string{"float4"}, typ: Float},
+		{words: []string{"float8"}, typ: Double},
+		{words: []string{"dec"}, typ: Decimal},
+		{words: []string{"fixed"}, typ: Decimal},
+		{words: []string{"geomcollection"}, typ: GeometryCollection},
+	}
+}
+
+// dataTypeAlias replaces the synonym of the data type of the column definition by its canonical type,
+// with its implicit length and charset. It also replaces the CHARSET shortcut, not supported by the SQL parser.
+// It returns true if the column is implicitly a unique key, as with SERIAL.
+// As with MySQL, a national data type can not declare another charset.
+func dataTypeAlias(def tokens) (tokens, bool, error) {
+	pos := def.next(def.next(-1))
+	if pos == len(def) {
+		return def, false, nil
+	}
+	var (
+		serial bool
+		res    = append(tokens{}, def[:pos]...)
+		rest   = def[pos:]
+	)
+	for _, a := range aliases() {
+		end, ok := def.words(pos, a.words...)
+		if !ok {
+			continue
+		}
+		res = append(res, token{typ: wordToken, val: a.typ.String()})
+		rest = def[end+1:]
+		if p := rest.next(-1); rest.at(p).is("(") {
+			res = append(res, rest[:rest.closing(p)+1]...)
+			rest = rest[min(rest.closing(p)+1, len(rest)):]
+		} else if a.length != "" {
+			res = append(res, lex("("+a.length+")")...)
+		}
+		if a.charset != "" {
+			if charsetAttribute(rest) {
+				return nil, false, ds.WrapErr("column national charset", ds.ErrInvalid)
+			}
+			res = append(res, lex(" character set "+a.charset)...)
+		}
+		if a.serial {
+			res = append(res, lex(" unsigned not null auto_increment")...)
+			serial = true
+		}
+		break
+	}
+	for p := 0; p < len(rest); p++ {
+		if rest[p].is("charset") {
+			res = append(res, lex("character set")...)
+			continue
+		}
+		if end, ok := rest.words(p, "serial", "default", "value"); ok && rest[p].is("serial") {
+			res = append(res, lex("not null auto_increment")...)
+			p, serial = end, true
+			continue
+		}
+		res = append(res, rest[p])
+	}
+	return res, serial, nil
+}
+
+// charsetAttribute returns true if the column definition declares its charset.
+func charsetAttribute(def tokens) bool {
+	for p := range def {
+		if _, ok := def.words(p, "character", "set"); ok || def[p].is("charset") {
+			return true
+		}
+	}
+	return false
+}
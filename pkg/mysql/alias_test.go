@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestEstimator_Parse_Aliases(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in      string
+			typ     mysql.DataType
+			size    uint64
+			charset string
+			notNull bool
+			// keys is the number of keys implied by the column, primary if so.
+			keys    int
+			primary bool
+			err     error
+		}{
+			"Bool":              {in: "c BOOL", typ: mysql.TinyInt, size: 1},
+			"Boolean":           {in: "c BOOLEAN NOT NULL", typ: mysql.TinyInt, size: 1, notNull: true},
+			"Serial":            {in: "c SERIAL", typ: mysql.BigInt, notNull: true, keys: 1},
+			"SerialDefault":     {in: "c INT8 SERIAL DEFAULT VALUE", typ: mysql.BigInt, notNull: true, keys: 1},
+			"Int4":              {in: "c INT4 UNSIGNED", typ: mysql.Int},
+			"MiddleInt":         {in: "c MIDDLEINT", typ: mysql.MediumInt},
+			"DoublePrecision":   {in: "c DOUBLE PRECISION", typ: mysql.Double},
+			"Float4":            {in: "c FLOAT4", typ: mysql.Float},
+			"Fixed":             {in: "c FIXED(10, 2)", typ: mysql.Decimal, size: 10},
+			"NChar":             {in: "c NCHAR(32) NOT NULL", typ: mysql.Char, size: 32, charset: "utf8", notNull: true},
+			"NVarChar":          {in: "c NVARCHAR(32)", typ: mysql.VarChar, size: 32, charset: "utf8"},
+			"National":          {in: "c NATIONAL CHARACTER VARYING(64)", typ: mysql.VarChar, size: 64, charset: "utf8"},
+			"NationalCharset":   {in: "c NVARCHAR(32) CHARACTER SET latin1", err: ds.ErrInvalid},
+			"NationalShortcut":  {in: "c NCHAR(32) CHARSET latin1", err: ds.ErrInvalid},
+			"NationalCharacter": {in: "c NATIONAL CHARACTER(3) CHARACTER SET ascii", err: ds.ErrInvalid},
+			"Truncated":         {in: "c DOUBLE PRECISION(", err: ds.ErrInvalid},
+			"CharacterVarying":  {in: "c CHARACTER VARYING(128) CHARSET latin1", typ: mysql.VarChar, size: 128, charset: "latin1"},
+			"LongVarChar":       {in: "c LONG VARCHAR", typ: mysql.MediumText, charset: mysql.DefaultCharset},
+			"LongVarBinary":     {in: "c LONG VARBINARY", typ: mysql.MediumBlob},
+			"Long":              {in: "c LONG", typ: mysql.MediumText, charset: mysql.DefaultCharset},
+			"GeomCollection":    {in: "c GEOMCOLLECTION", typ: mysql.GeometryCollection},
+			"Key":               {in: "c INT KEY", typ: mysql.Int, notNull: true, keys: 1, primary: true},
+			"PrimaryKey":        {in: "c INT PRIMARY KEY", typ: mysql.Int, notNull: true, keys: 1, primary: true},
+			"TablePrimaryKey":   {in: "c INT, PRIMARY KEY (c)", typ: mysql.Int, notNull: true, keys: 1, primary: true},
+			"Unique":            {in: "c CHAR(3) UNIQUE", typ: mysql.Char, size: 3, charset: mysql.DefaultCharset, keys: 1},
+			"UniqueKey":         {in: "c VARCHAR(255) UNIQUE KEY", typ: mysql.VarChar, size: 255, charset: mysql.DefaultCharset, keys: 1},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := mysql.Parse(strings.NewReader("CREATE TABLE t (" + tt.in + ")"))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			tb := dbs[0].Tables[0]
+			c := tb.Columns[0]
+			are.Equal(tt.typ, c.DataType) // mismatch data type
+			if tt.size > 0 {
+				are.Equal(tt.size, c.DataSize) // mismatch data size
+			}
+			if tt.charset != "" {
+				are.Equal(tt.charset, c.Charset) // mismatch charset
+			}
+			are.Equal(tt.notNull, c.NotNull)    // mismatch not null
+			are.Equal(tt.keys, len(tb.Indexes)) // mismatch number of keys
+			if tt.keys > 0 {
+				are.Equal(tt.primary, tb.Indexes[0].Primary) // mismatch primary key
+			}
+		})
+	}
+}
@@ -0,0 +1,515 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/xwb1989/sqlparser"
+)
+
+// alteration is an ALTER TABLE statement, or a CREATE INDEX or DROP INDEX one, converted to it.
+type alteration struct {
+	database,
+	table string
+	// toDatabase and toTable name the table once renamed, if so.
+	toDatabase,
+	toTable string
+	specs        []tokens
+	partitioning Partitioning
+}
+
+// alterStatement returns the alteration of the table described by the statement, if any.
+// See https://dev.mysql.com/doc/refman/8.0/en/alter-table.html
+func alterStatement(stmt tokens) (res alteration, ok bool, err error) {
+	pos, ok := stmt.words(0, "alter", "table")
+	if !ok {
+		return indexStatement(stmt)
+	}
+	res.database, res.table, pos = stmt.name(stmt.next(pos))
+	rest := stmt[pos+1:]
+	rest, res.partitioning, err = partitioning(rest)
+	if err != nil {
+		return res, ok, err
+	}
+	for _, spec := range rest.separate(0, len(rest)) {
+		if db, name, rename := tableRename(spec); rename {
+			res.toDatabase, res.toTable = db, name
+			continue
+		}
+		res.specs = append(res.specs, spec)
+	}
+	return res, ok, nil
+}
+
+// tableRename returns the new name of the table, with its qualifier if any,
+// if the specification renames the table, like RENAME TO shop.user.
+func tableRename(spec tokens) (qualifier, name string, ok bool) {
+	pos, ok := spec.words(0, "rename")
+	if !ok {
+		return
+	}
+	pos = spec.next(pos)
+	switch w := spec.at(pos); {
+	case w.is("column"), w.is("index"), w.is("key"):
+		return "", "", false
+	case w.is("to"), w.is("as"):
+		pos = spec.next(pos)
+	}
+	qualifier, name, _ = spec.name(pos)
+	return qualifier, name, true
+}
+
+// indexStatement converts the CREATE INDEX and DROP INDEX statements to an alteration of their table.
+// See https://dev.mysql.com/doc/refman/8.0/en/create-index.html
+func indexStatement(stmt tokens) (res alteration, ok bool, err error) {
+	if pos, ok := stmt.words(0, "drop", "index"); ok {
+		_, _, end := stmt.name(stmt.next(pos))
+		on, ok := stmt.words(stmt.next(end), "on")
+		if !ok {
+			return res, false, nil
+		}
+		res.database, res.table, _ = stmt.name(stmt.next(on))
+		res.specs = []tokens{append(lex("DROP INDEX "), stmt[stmt.next(pos):end+1]...)}
+		return res, true, nil
+	}
+	start, ok := stmt.words(0, "create")
+	if !ok {
+		return res, false, nil
+	}
+	start = stmt.next(start)
+	pos := start
+	if t := stmt.at(pos); t.is("unique") || t.is("fulltext") || t.is("spatial") {
+		pos = stmt.next(pos)
+	}
+	if !stmt.at(pos).is("index") {
+		return res, false, nil
+	}
+	for ; pos < len(stmt) && !stmt.at(pos).is("on"); pos = stmt.next(pos) {
+	}
+	if pos == len(stmt) {
+		return res, false, ds.WrapErr("index table", ds.ErrMissing)
+	}
+	var end int
+	res.database, res.table, end = stmt.name(stmt.next(pos))
+	def := append(lex("ADD "), stmt[start:pos]...)
+	res.specs = []tokens{append(def, stmt[end+1:]...)}
+	return res, true, nil
+}
+
+// alterTable applies the alteration on the table of the database, like ADD COLUMN, MODIFY, DROP INDEX or ENGINE.
+// The specifications without impact on the sizes, like the foreign keys, are ignored.
+// The table is only altered if all of them succeed, then renamed, and moved to the other database if needed.
+func (s Storage) alterTable(dbName string, a alteration) error {
+	i, p, err := s.table(dbName, a.table)
+	if err != nil {
+		return err
+	}
+	rename := a.toTable != "" && (a.toDatabase != dbName || a.toTable != a.table)
+	if rename {
+		if _, _, err = s.table(a.toDatabase, a.toTable); err == nil {
+			return fmt.Errorf("table: %s.%s: %w", a.toDatabase, a.toTable, ds.ErrInvalid)
+		}
+	}
+	t := s[i].Tables[p].clone()
+	for _, spec := range a.specs {
+		err = t.alter(spec)
+		if err != nil {
+			return err
+		}
+	}
+	if a.partitioning.Method != "" {
+		t.Partitioning = a.partitioning
+	}
+	t.diagnose()
+	err = t.Analyze()
+	if err != nil {
+		return err
+	}
+	s[i].Tables[p] = t
+	if !rename {
+		return nil
+	}
+	return s.renameTable(dbName, a.table, a.toDatabase, a.toTable)
+}
+
+// clone returns a copy of the table, to alter it without changing the original one.
+func (t Table) clone() Table {
+	t.Columns = append([]Column(nil), t.Columns...)
+	t.Diagnostics = append([]Diagnostic(nil), t.Diagnostics...)
+	idx := make([]Index, len(t.Indexes))
+	for p, k := range t.Indexes {
+		k.Parts = append([]KeyPart(nil), k.Parts...)
+		idx[p] = k
+	}
+	t.Indexes = idx
+	return t
+}
+
+// alter applies the specification of the ALTER TABLE statement on the table.
+func (t *Table) alter(spec tokens) error {
+	pos := spec.next(-1)
+	switch w := spec.at(pos); {
+	case w.is("add"):
+		return t.add(spec, spec.next(pos))
+	case w.is("drop"):
+		return t.drop(spec, spec.next(pos))
+	case w.is("modify"):
+		def := spec[spec.optional(spec.next(pos), "column"):]
+		_, name, _ := def.name(def.next(-1))
+		return t.modify(name, def)
+	case w.is("change"):
+		_, name, end := spec.name(spec.optional(spec.next(pos), "column"))
+		return t.modify(name, spec[end+1:])
+	case w.is("rename"):
+		return t.rename(spec, spec.next(pos))
+	case w.is("convert"):
+		return t.convert(spec, spec.next(pos))
+	case w.is("remove"):
+		if _, ok := spec.words(spec.next(pos), "partitioning"); ok {
+			t.Partitioning = Partitioning{}
+		}
+		return nil
+	default:
+		return t.setOptions(spec, pos)
+	}
+}
+
+// optional returns the position of the next meaningful token if the one at this position is the word.
+func (ts tokens) optional(pos int, word string) int {
+	if ts.at(pos).is(word) {
+		return ts.next(pos)
+	}
+	return pos
+}
+
+// add adds the columns or the index defined from this position.
+func (t *Table) add(spec tokens, pos int) error {
+	switch {
+	case spec.at(pos).is("partition"):
+		return nil
+	case spec[pos:].indexDefinition():
+		return t.addDefinitions([]tokens{spec[pos:]}, notFound)
+	}
+	pos = spec.optional(pos, "column")
+	if spec.at(pos).is("(") {
+		defs, _ := spec.list(pos)
+		return t.addDefinitions(defs, notFound)
+	}
+	def, at, err := t.position(spec[pos:])
+	if err != nil {
+		return err
+	}
+	return t.addDefinitions([]tokens{def}, at)
+}
+
+// position returns the column definition without its FIRST or AFTER clause, if any,
+// and the position of the column in the table, or notFound without clause.
+func (t *Table) position(def tokens) (tokens, int, error) {
+	var last, prev = notFound, notFound
+	for p := def.next(-1); p < len(def); p = def.next(p) {
+		prev, last = last, p
+	}
+	switch {
+	case def.at(last).is("first"):
+		return def[:last], 0, nil
+	case def.at(prev).is("after"):
+		_, name, _ := def.name(last)
+		i := t.columnIndex(name)
+		if i == notFound {
+			return nil, 0, ds.WrapErr("column "+name, ds.ErrInvalid)
+		}
+		return def[:prev], i + 1, nil
+	default:
+		return def, notFound, nil
+	}
+}
+
+// placeholder is the name of the column used to parse definitions out of a CREATE TABLE statement.
+const placeholder = "ds_placeholder"
+
+// define parses the column and index definitions as the ones of a CREATE TABLE statement.
+// It returns the table specification, with the columns and their diagnostics.
+func define(defs []tokens, charset string) (*sqlparser.TableSpec, extension, []Column, []Diagnostic, error) {
+	stmt := lex("CREATE TABLE " + placeholder + " (" + placeholder + " INT")
+	for _, def := range defs {
+		stmt = append(stmt, token{typ: symbolToken, val: ","})
+		stmt = append(stmt, def...)
+	}
+	stmt = append(stmt, lex("\n)")...)
+	stmt, ext, err := extend(stmt)
+	if err != nil {
+		return nil, ext, nil, nil, err
+	}
+	res, err := sqlparser.ParseNext(sqlparser.NewStringTokenizer(stmt.sql()))
+	ddl, ok := res.(*sqlparser.DDL)
+	if err != nil || !ok || ddl.TableSpec == nil {
+		return nil, ext, nil, nil, ds.WrapErr("definition", ds.ErrInvalid)
+	}
+	cols, diags, err := columns(ddl.TableSpec, charset)
+	if err != nil {
+		return nil, ext, nil, nil, err
+	}
+	cols = cols[1:]
+	if len(cols) == 0 && len(ddl.TableSpec.Indexes) == 0 && len(ext.fullText) == 0 {
+		return nil, ext, nil, nil, ds.WrapErr("definition", ds.ErrMissing)
+	}
+	diags = append(diags, ext.setColumns(cols)...)
+	return ddl.TableSpec, ext, cols, diags, nil
+}
+
+// addDefinitions adds the columns and the indexes defined to the table, the columns at this position.
+// Without position, the columns are added after the others.
+func (t *Table) addDefinitions(defs []tokens, at int) error {
+	spec, ext, cols, diags, err := define(defs, t.Charset)
+	if err != nil {
+		return err
+	}
+	for _, c := range cols {
+		if t.columnIndex(c.Name) != notFound {
+			return ds.WrapErr("column "+c.Name, ds.ErrInvalid)
+		}
+	}
+	if at == notFound {
+		at = len(t.Columns)
+	}
+	t.Columns = append(t.Columns[:at], append(cols, t.Columns[at:]...)...)
+	t.addDiagnostics(diags)
+	err = ext.addHiddenColumns(t, t.Charset)
+	if err != nil {
+		return err
+	}
+	return t.addKeys(spec, ext)
+}
+
+// addDiagnostics adds the diagnostics of the columns of the table.
+func (t *Table) addDiagnostics(diags []Diagnostic) {
+	for _, d := range diags {
+		d.Table = t.Name
+		t.Diagnostics = append(t.Diagnostics, d)
+	}
+}
+
+// dropDiagnostics removes the diagnostics of the column.
+func (t *Table) dropDiagnostics(column string) {
+	var res []Diagnostic
+	for _, d := range t.Diagnostics {
+		if d.Column != column {
+			res = append(res, d)
+		}
+	}
+	t.Diagnostics = res
+}
+
+// modify replaces the named column by the definition, with its FIRST or AFTER clause if any.
+func (t *Table) modify(name string, def tokens) error {
+	i := t.columnIndex(name)
+	if i == notFound {
+		return ds.WrapErr("column "+name, ds.ErrInvalid)
+	}
+	def, at, err := t.position(def)
+	if err != nil {
+		return err
+	}
+	spec, ext, cols, diags, err := define([]tokens{def}, t.Charset)
+	if err != nil {
+		return err
+	}
+	if len(cols) != 1 {
+		return ds.WrapErr("column definition", ds.ErrInvalid)
+	}
+	c := cols[0]
+	if c.Name != name && t.columnIndex(c.Name) != notFound {
+		return ds.WrapErr("column "+c.Name, ds.ErrInvalid)
+	}
+	t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+	if at == notFound {
+		at = i
+	} else if at > i {
+		at--
+	}
+	t.Columns = append(t.Columns[:at], append([]Column{c}, t.Columns[at:]...)...)
+	t.dropDiagnostics(name)
+	t.addDiagnostics(diags)
+	t.renameKeyParts(name, c.Name)
+	t.refreshKeys()
+	return t.addKeys(spec, ext)
+}
+
+// drop drops the column or the index named from this position.
+func (t *Table) drop(spec tokens, pos int) error {
+	switch w := spec.at(pos); {
+	case w.is("index"), w.is("key"):
+		_, name, _ := spec.name(spec.next(pos))
+		return t.dropIndex(name)
+	case w.is("primary"):
+		return t.dropIndex(primaryKeyName)
+	case w.is("foreign"), w.is("check"), w.is("constraint"), w.is("partition"):
+		return nil
+	default:
+		_, name, _ := spec.name(spec.optional(pos, "column"))
+		i := t.columnIndex(name)
+		if i == notFound {
+			return ds.WrapErr("column "+name, ds.ErrInvalid)
+		}
+		t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+		t.dropDiagnostics(name)
+		t.refreshKeys()
+		return nil
+	}
+}
+
+// dropIndex drops the named index.
+func (t *Table) dropIndex(name string) error {
+	for p, k := range t.Indexes {
+		if k.Name == name || k.Primary && name == primaryKeyName {
+			t.Indexes = append(t.Indexes[:p], t.Indexes[p+1:]...)
+			return nil
+		}
+	}
+	return ds.WrapErr("index "+name, ds.ErrInvalid)
+}
+
+// rename renames the column or the index named from this position.
+// The table itself is renamed by the storage, see tableRename.
+func (t *Table) rename(spec tokens, pos int) error {
+	w := spec.at(pos)
+	if !w.is("column") && !w.is("index") && !w.is("key") {
+		return ds.WrapErr("rename", ds.ErrInvalid)
+	}
+	_, from, end := spec.name(spec.next(pos))
+	end, ok := spec.words(spec.next(end), "to")
+	if !ok {
+		return ds.WrapErr("rename", ds.ErrInvalid)
+	}
+	_, to, _ := spec.name(spec.next(end))
+	if w.is("column") {
+		return t.renameColumn(from, to)
+	}
+	return t.renameIndex(from, to)
+}
+
+func (t *Table) renameColumn(from, to string) error {
+	i := t.columnIndex(from)
+	if i == notFound || t.columnIndex(to) != notFound {
+		return ds.WrapErr("column "+from, ds.ErrInvalid)
+	}
+	t.Columns[i].Name = to
+	for p := range t.Diagnostics {
+		if t.Diagnostics[p].Column == from {
+			t.Diagnostics[p].Column = to
+		}
+	}
+	t.renameKeyParts(from, to)
+	t.refreshKeys()
+	return nil
+}
+
+func (t *Table) renameIndex(from, to string) error {
+	for p := range t.Indexes {
+		if t.Indexes[p].Name == from {
+			t.Indexes[p].Name = to
+			return nil
+		}
+	}
+	return ds.WrapErr("index "+from, ds.ErrInvalid)
+}
+
+// renameKeyParts renames the key parts of the renamed column.
+func (t *Table) renameKeyParts(from, to string) {
+	for i := range t.Indexes {
+		for j := range t.Indexes[i].Parts {
+			if t.Indexes[i].Parts[j].Name == from {
+				t.Indexes[i].Parts[j].Name = to
+			}
+		}
+	}
+}
+
+// refreshKeys updates the columns of the key parts, drops the key parts of the dropped columns,
+// then the keys without any key part.
+func (t *Table) refreshKeys() {
+	var res []Index
+	for _, k := range t.Indexes {
+		var parts []KeyPart
+		for _, p := range k.Parts {
+			if i := t.columnIndex(p.Name); i != notFound {
+				p.Column = t.Columns[i]
+				parts = append(parts, p)
+			}
+		}
+		if len(parts) > 0 {
+			k.Parts = parts
+			res = append(res, k)
+		}
+	}
+	t.Indexes = res
+}
+
+// convert converts the string columns to the charset named from this position, like CONVERT TO CHARACTER SET latin1.
+func (t *Table) convert(spec tokens, pos int) error {
+	end, ok := spec.words(pos, "to", "character", "set")
+	if !ok {
+		if end, ok = spec.words(pos, "to", "charset"); !ok {
+			return ds.WrapErr("convert", ds.ErrInvalid)
+		}
+	}
+	t.Charset = Charset(spec.at(spec.next(end)).text())
+	for p := range t.Columns {
+		if t.Columns[p].DataType.IsString() {
+			t.Columns[p].Charset = t.Charset
+		}
+	}
+	t.refreshKeys()
+	return nil
+}
+
+// setOptions sets the table options declared from this position, like ENGINE=MyISAM ROW_FORMAT=FIXED.
+// A new engine resets the row format, if not declared.
+func (t *Table) setOptions(spec tokens, pos int) error {
+	var changed, declared bool
+	for pos < len(spec) {
+		name := strings.ToLower(spec.at(pos).val)
+		switch name {
+		case "default":
+			pos = spec.next(pos)
+			continue
+		case "character":
+			pos = spec.next(pos)
+			name = characterSet
+		}
+		pos = spec.optional(spec.next(pos), equal)
+		v := spec.at(pos).text()
+		switch name {
+		case engine:
+			e := ToEngine(v)
+			if e == "" {
+				return ds.WrapErr("table engine "+v, ds.ErrInvalid)
+			}
+			changed = changed || e != t.Engine
+			t.Engine = e
+		case rowFormat:
+			t.RowFormat, declared = ToRowFormat(v), true
+		case keyBlockSize:
+			var err error
+			t.KeyBlockSize, err = strconv.ParseUint(v, base10, bits64)
+			if err != nil {
+				return ds.WrapErr("table key block size", ds.ErrInvalid)
+			}
+		case compression:
+			t.Compression = ToCompression(v)
+		case characterSet:
+			t.Charset = Charset(v)
+		}
+		pos = spec.next(pos)
+	}
+	if changed && !declared {
+		t.RowFormat = UnknownRowFormat
+	}
+	return nil
+}
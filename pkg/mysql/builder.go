@@ -0,0 +1,88 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import "github.com/rvflash/ds/pkg/ds"
+
+// NewTable returns a new table with this name and engine.
+// The charset is used by default for the columns without one.
+func NewTable(name string, engine Engine, charset string) *Table {
+	return &Table{
+		Name:    name,
+		Engine:  engine,
+		Charset: Charset(charset),
+	}
+}
+
+// AddColumn adds the column to the table. Without charset, the one of the table is used.
+// A column with an unknown data type is excluded from the estimation, see the table diagnostics.
+func (t *Table) AddColumn(c Column) error {
+	if c.Name == "" {
+		return ds.WrapErr("column name", ds.ErrMissing)
+	}
+	if t.columnIndex(c.Name) != notFound {
+		return ds.WrapErr("column "+c.Name, ds.ErrInvalid)
+	}
+	c.Charset = Charset(c.Charset, t.Charset)
+	t.Columns = append(t.Columns, c)
+	t.diagnose()
+	return nil
+}
+
+// AddIndex adds the index to the table. Its key parts must be named as the table's columns.
+// With InnoDB, the first FULLTEXT index also adds the hidden FTS_DOC_ID column and its index.
+func (t *Table) AddIndex(k Index) error {
+	if len(k.Parts) == 0 {
+		return ds.WrapErr("key part", ds.ErrMissing)
+	}
+	if k.Type != FullTextIndex {
+		k.Parts = append([]KeyPart(nil), k.Parts...)
+		return t.addKey(k)
+	}
+	ft := fullText{
+		name:    k.Name,
+		columns: make([]string, len(k.Parts)),
+		words:   k.Words,
+		length:  k.WordLength,
+	}
+	for p, v := range k.Parts {
+		ft.columns[p] = v.Name
+	}
+	return t.addFullTextKey(ft)
+}
+
+// NewDatabase returns a new database with this name and its default charset.
+func NewDatabase(name, charset string) *Database {
+	return &Database{
+		Name:    name,
+		Charset: Charset(charset),
+	}
+}
+
+// AddTable analyzes the table and adds it to the database.
+func (d *Database) AddTable(t Table) error {
+	for _, v := range d.Tables {
+		if v.Name == t.Name {
+			return ds.WrapErr("table "+t.Name, ds.ErrInvalid)
+		}
+	}
+	err := t.Analyze()
+	if err != nil {
+		return err
+	}
+	d.Tables = append(d.Tables, t)
+	return nil
+}
+
+// AddDatabase adds the database to the storage.
+func (s Storage) AddDatabase(d Database) (Storage, error) {
+	if d.Name == "" {
+		return s, ds.WrapErr("database name", ds.ErrMissing)
+	}
+	if _, err := s.get(d.Name); err == nil {
+		return s, ds.WrapErr("database "+d.Name, ds.ErrInvalid)
+	}
+	return append(s, d), nil
+}
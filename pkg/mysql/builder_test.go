@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestTable_AddColumn(t *testing.T) {
+	var (
+		are = is.New(t)
+		tb  = mysql.NewTable("t", mysql.InnoDB, "")
+	)
+	are.True(errors.Is(tb.AddColumn(mysql.Column{}), ds.ErrMissing))                    // expected missing name
+	are.NoErr(tb.AddColumn(mysql.Column{Name: "a", DataType: mysql.Char, DataSize: 2})) // unexpected error
+	are.True(errors.Is(tb.AddColumn(mysql.Column{Name: "a"}), ds.ErrInvalid))           // expected duplicate
+	are.NoErr(tb.AddColumn(mysql.Column{Name: "b", DataType: "unknown"}))               // unexpected error
+	are.Equal(mysql.DefaultCharset, tb.Columns[0].Charset)                              // mismatch charset
+	are.True(tb.Columns[1].Excluded)                                                    // expected excluded column
+	are.Equal(1, len(tb.Diagnostics))                                                   // mismatch diagnostics
+}
+
+func TestTable_AddIndex(t *testing.T) {
+	var (
+		are = is.New(t)
+		tb  = mysql.NewTable("t", mysql.InnoDB, "")
+		dt  = map[string]struct {
+			in  mysql.Index
+			err error
+		}{
+			"Default": {err: ds.ErrMissing},
+			"Unknown": {in: mysql.Index{Parts: []mysql.KeyPart{{Column: mysql.Column{Name: "b"}}}}, err: ds.ErrInvalid},
+			"Blob":    {in: mysql.Index{Parts: []mysql.KeyPart{{Column: mysql.Column{Name: "body"}}}}, err: ds.ErrMissing},
+			"OK":      {in: mysql.Index{Name: "a", Parts: []mysql.KeyPart{{Column: mysql.Column{Name: "a"}}}}},
+			"FullText": {in: mysql.Index{
+				Name: "ft", Type: mysql.FullTextIndex, Parts: []mysql.KeyPart{{Column: mysql.Column{Name: "body"}}},
+			}},
+		}
+	)
+	are.NoErr(tb.AddColumn(mysql.Column{Name: "a", DataType: mysql.Int}))     // unexpected error
+	are.NoErr(tb.AddColumn(mysql.Column{Name: "body", DataType: mysql.Text})) // unexpected error
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			are.True(errors.Is(tb.AddIndex(tt.in), tt.err)) // mismatch error
+		})
+	}
+	are.Equal(3, len(tb.Indexes)) // expected the FULLTEXT index with the FTS_DOC_ID one
+	are.Equal(3, len(tb.Columns)) // expected the hidden FTS_DOC_ID column
+}
+
+func TestDatabase_AddTable(t *testing.T) {
+	var (
+		are = is.New(t)
+		db  = mysql.NewDatabase("db", "")
+		tb  = mysql.NewTable("t", mysql.InnoDB, "")
+	)
+	are.True(errors.Is(db.AddTable(*tb), ds.ErrMissing)) // expected missing column
+	are.NoErr(tb.AddColumn(mysql.Column{Name: "a", DataType: mysql.Int}))
+	are.NoErr(db.AddTable(*tb))                               // unexpected error
+	are.True(errors.Is(db.AddTable(*tb), ds.ErrInvalid))      // expected duplicate
+	are.Equal(mysql.DynamicRowFormat, db.Tables[0].RowFormat) // mismatch row format
+
+	s, err := mysql.Storage{}.AddDatabase(*db)
+	are.NoErr(err) // unexpected error
+	_, err = s.AddDatabase(*db)
+	are.True(errors.Is(err, ds.ErrInvalid)) // expected duplicate
+	_, err = s.AddDatabase(mysql.Database{})
+	are.True(errors.Is(err, ds.ErrMissing)) // expected missing name
+}
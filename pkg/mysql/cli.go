@@ -0,0 +1,273 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/template"
+
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/ds/render"
+)
+
+// NewCLI returns the MySQL estimator as a subcommand, reporting its diagnostics in the given writer.
+func NewCLI(diagnostics io.Writer) *CLI {
+	return &CLI{diagnostics: diagnostics}
+}
+
+// CLI is the MySQL estimator as a subcommand. It implements the ds.Estimator, ds.Configurable and ds.Watcher interfaces.
+type CLI struct {
+	Config
+	diagnostics io.Writer
+}
+
+// Name implements the ds.Estimator interface.
+func (c *CLI) Name() string {
+	return Command
+}
+
+// Usage implements the ds.Estimator interface.
+func (c *CLI) Usage() string {
+	return "estimates the data sizes of MySQL databases, tables, columns and keys based on SQL statements"
+}
+
+// DefaultConfig returns the default settings of the MySQL estimator.
+func DefaultConfig() Config {
+	return Config{
+		Precision:          DefaultPrecision,
+		FullTextWords:      DefaultFullTextWords,
+		FullTextWordLength: DefaultFullTextWordLength,
+		Vertices:           DefaultVertices,
+		PerN:               ds.Scenarios{DefaultPerN},
+		Columns:            "type,row,n",
+		Format:             render.TableFormat.String(),
+		Units:              siUnits,
+		Charset:            DefaultCharset,
+		DefaultEngine:      InnoDB.String(),
+		PageSize:           DefaultPageSize,
+		WatchInterval:      DefaultWatchInterval,
+	}
+}
+
+// SetFlags implements the ds.Estimator interface.
+func (c *CLI) SetFlags(fs *flag.FlagSet) {
+	d := DefaultConfig()
+	s := "batch mode, print results using comma as the column separator, with each row on a new line"
+	fs.BoolVar(&c.Batch, "B", d.Batch, s)
+	s = "expected sizes, display the expected sizes next to the minimum and maximum ones"
+	fs.BoolVar(&c.Expected, "e", d.Expected, s)
+	s = "strict mode, fail on any column whose size can not be estimated, like with an unknown data type, " +
+		"or on any statement that can not be parsed"
+	fs.BoolVar(&c.Strict, "s", d.Strict, s)
+	s = "verbose mode, produce more output about what the program does"
+	fs.BoolVar(&c.Verbose, "v", d.Verbose, s)
+	s = "number of decimals to display"
+	fs.Uint64Var(&c.Precision, "p", d.Precision, s)
+	s = "numbers of lines to considerate by table, separated by commas to estimate several scenarios, like 1e6,1e8,1e9"
+	c.PerN = d.PerN
+	fs.Var(&c.PerN, "n", s)
+	s = "compression ratio of the compressed tables, if zero, it is assumed based on data types"
+	fs.Float64Var(&c.CompressionRatio, "c", d.CompressionRatio, s)
+	s = "average number of distinct words by row in the FULLTEXT indexes"
+	fs.Uint64Var(&c.FullTextWords, "fw", d.FullTextWords, s)
+	s = "average number of characters by word in the FULLTEXT indexes"
+	fs.Uint64Var(&c.FullTextWordLength, "fl", d.FullTextWordLength, s)
+	s = "path of the file with the profiles of the columns, one by line, like: db.table.column avg=24 null=0.1"
+	fs.StringVar(&c.Profiles, "P", d.Profiles, s)
+	s = "number of points by value of the spatial columns"
+	fs.Uint64Var(&c.Vertices, "gv", d.Vertices, s)
+	s = "output format: table, tree, csv, json, markdown or html"
+	fs.StringVar(&c.Format, "o", d.Format, s)
+	s = "order of the tables, and in verbose mode, of the columns and keys, by descending size: min or max"
+	fs.StringVar(&c.Order, "sort", d.Order, s)
+	s = "number of largest tables to report by database, zero meaning all of them"
+	fs.Uint64Var(&c.Top, "top", d.Top, s)
+	s = "shell pattern of the names of the databases to report, like shop_*"
+	fs.StringVar(&c.Database, "db", d.Database, s)
+	s = "shell pattern of the names of the tables to report, like log_*"
+	fs.StringVar(&c.Table, "tb", d.Table, s)
+	s = "engine of the tables to report: InnoDB or MyISAM"
+	fs.StringVar(&c.Engine, "engine", d.Engine, s)
+	s = "units of the sizes: si (KB, MB...), iec (KiB, MiB...) or a unit to use for all of them, like GiB"
+	fs.StringVar(&c.Units, "u", d.Units, s)
+	s = "columns of the report, after the name of the data: type, row, n, engine, charset or nullable"
+	fs.StringVar(&c.Columns, "cols", d.Columns, s)
+	s = "charset of the databases declared without charset"
+	fs.StringVar(&c.Charset, "charset", d.Charset, s)
+	s = "engine of the tables declared without engine: InnoDB or MyISAM"
+	fs.StringVar(&c.DefaultEngine, "default-engine", d.DefaultEngine, s)
+	s = "InnoDB page size, in bytes or with a unit, like 32KiB"
+	c.PageSize = d.PageSize
+	fs.Var(&c.PageSize, "page-size", s)
+	s = "path of the Go text template used to render the report, overloading the output format"
+	fs.StringVar(&c.Template, "tpl", d.Template, s)
+	s = "interactive mode, apply the SQL statements line by line and print the size delta of each change"
+	fs.BoolVar(&c.Interactive, "i", d.Interactive, s)
+	s = "watch mode, estimate the SQL files or directories again on each change and print the size delta"
+	fs.BoolVar(&c.WatchMode, "watch", d.WatchMode, s)
+	s = "interval between two polls of the watched files"
+	fs.DurationVar(&c.WatchInterval, "watch-interval", d.WatchInterval, s)
+}
+
+// Settings implements the ds.Configurable interface.
+func (c *CLI) Settings() interface{} {
+	return &c.Config
+}
+
+// Run implements the ds.Estimator interface.
+func (c *CLI) Run(r io.Reader, w io.Writer) error {
+	return c.RunContext(context.Background(), r, w)
+}
+
+// RunContext is like Run, but the estimation stops with the error of the context once done.
+// The interactive mode ignores the context.
+func (c *CLI) RunContext(ctx context.Context, r io.Reader, w io.Writer) error {
+	e, err := c.estimator()
+	if err != nil {
+		return err
+	}
+	if c.Interactive {
+		return e.Interact(r, w)
+	}
+	return e.RunContext(ctx, r, w)
+}
+
+// Watching implements the ds.Watcher interface.
+func (c *CLI) Watching() bool {
+	return c.WatchMode
+}
+
+// Watch implements the ds.Watcher interface.
+// It watches the files until an interrupt signal.
+func (c *CLI) Watch(paths []string, w io.Writer) error {
+	e, err := c.estimator()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt)
+	defer signal.Stop(quit)
+	go func() {
+		select {
+		case <-quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return e.Watch(ctx, w, paths...)
+}
+
+// estimator returns the estimator configured by the settings.
+func (c *CLI) estimator() (*Estimator, error) {
+	p, err := openProfiles(c.Profiles)
+	if err != nil {
+		return nil, err
+	}
+	f, err := render.ToFormat(c.Format)
+	if err != nil {
+		return nil, err
+	}
+	binary, unit, err := units(c.Units)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := render.ToColumns(c.Columns)
+	if err != nil {
+		return nil, err
+	}
+	o, err := ds.ToOrder(c.Order)
+	if err != nil {
+		return nil, err
+	}
+	t, err := openTemplate(c.Template)
+	if err != nil {
+		return nil, err
+	}
+	var engine Engine
+	if c.Engine != "" {
+		// An empty engine is the default one for the tables, but any one for the filter.
+		engine = ToEngine(c.Engine)
+		if engine == "" {
+			return nil, ds.WrapErr("engine "+c.Engine, ds.ErrInvalid)
+		}
+	}
+	defaultEngine := ToEngine(c.DefaultEngine)
+	if defaultEngine == "" {
+		return nil, ds.WrapErr("default engine "+c.DefaultEngine, ds.ErrInvalid)
+	}
+	return Estimate(
+		SetPrecision(c.Precision),
+		SetScenarios(c.PerN...),
+		SetColumns(cols...),
+		SetFormat(f),
+		SetBatchMode(c.Batch),
+		SetVerbose(c.Verbose),
+		SetStrictMode(c.Strict),
+		SetExpected(c.Expected),
+		SetProfiles(p),
+		SetDiagnosticOutput(c.diagnostics),
+		SetCompressionRatio(c.CompressionRatio),
+		SetFullTextWords(c.FullTextWords),
+		SetFullTextWordLength(c.FullTextWordLength),
+		SetVertices(c.Vertices),
+		SetBinaryUnits(binary),
+		SetUnit(unit),
+		SetOrder(o),
+		SetTop(c.Top),
+		SetDatabaseFilter(c.Database),
+		SetTableFilter(c.Table),
+		SetEngineFilter(engine),
+		SetDefaultCharset(c.Charset),
+		SetDefaultEngine(defaultEngine),
+		SetPageSize(uint64(c.PageSize)),
+		SetTemplate(t),
+		SetWatchInterval(c.WatchInterval),
+	)
+}
+
+// Systems of units.
+const (
+	siUnits  = "si"
+	iecUnits = "iec"
+)
+
+// units returns the system of units or the unit to use for all the sizes, based on its name.
+func units(s string) (binary bool, unit ds.Unit, err error) {
+	switch strings.ToLower(s) {
+	case "", siUnits:
+		return false, 0, nil
+	case iecUnits:
+		return true, 0, nil
+	default:
+		unit, err = ds.ParseUnit(s)
+		return false, unit, err
+	}
+}
+
+func openTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return render.ParseTemplateFile(path)
+}
+
+func openProfiles(path string) (Profiles, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return ReadProfiles(f)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Column is a table's column.
+type Column struct {
+	Name     string
+	Charset  string
+	DataSize uint64
+	DataType DataType
+	NotNull  bool
+	// Expression is the expression used to compute the values of a generated column.
+	Expression string
+	Generated  Generated
+	Invisible  bool
+	// SRID is the spatial reference system identifier of a spatial column, zero meaning the Cartesian plane.
+	SRID uint32
+	// Vertices is the number of points by value of a spatial column. If zero, a default value is used.
+	Vertices uint64
+	// Excluded is true if the column can not be estimated, see the table diagnostics.
+	Excluded bool
+	Profile  Profile
+}
+
+// Size implements the ds.Data interface.
+// An excluded column has no size, and a column with NULL values may have none.
+func (c Column) Size() (min, max uint64) {
+	min, max = c.valueSize()
+	if c.nullable() {
+		min = 0
+	}
+	return
+}
+
+func (c Column) valueSize() (min, max uint64) {
+	if c.Excluded {
+		return both(0)
+	}
+	if c.DataType.IsSpatial() {
+		return c.DataType.Size(c.Vertices, c.Charset)
+	}
+	return c.DataType.Size(c.DataSize, c.Charset)
+}
+
+// Kind implements the ds.Data interface.
+func (c Column) Kind() string {
+	var a []string
+	if c.DataSize > 0 && !c.DataType.IsInt() {
+		a = append(a, ds.Unit(c.DataSize).String())
+	}
+	if c.DataType.IsString() {
+		a = append(a, c.Charset)
+	}
+	if c.Vertices > 0 && c.DataType.IsSpatial() && c.DataType != Point {
+		a = append(a, fmt.Sprintf("%d %s", c.Vertices, points))
+	}
+	if c.SRID > 0 {
+		a = append(a, fmt.Sprintf("%s %d", srid, c.SRID))
+	}
+	if c.Generated != NotGenerated {
+		a = append(a, c.Generated.String())
+	}
+	if c.Invisible {
+		a = append(a, invisible)
+	}
+	if s := strings.Join(a, ", "); s != "" {
+		return fmt.Sprintf("%s(%s)", c.DataType.String(), s)
+	}
+	return c.DataType.String()
+}
+
+const (
+	invisible = "invisible"
+	points    = "points"
+	srid      = "srid"
+)
+
+// Flagged implements the ds.Flagger interface.
+// A column is flagged when it has been excluded from the estimation.
+func (c Column) Flagged() bool {
+	return c.Excluded
+}
+
+// hidden returns true if the column is the hidden one of a functional key part.
+func (c Column) hidden() bool {
+	return strings.HasPrefix(c.Name, hiddenPrefix)
+}
+
+// String implements the ds.Data interface.
+func (c Column) String() string {
+	return c.Name
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// ToCompression returns the page compression algorithm based on the given name.
+func ToCompression(s string) Compression {
+	return Compression(strings.ToLower(strings.Trim(s, `'"`)))
+}
+
+// Compression represents an InnoDB transparent page compression algorithm.
+type Compression string
+
+// List of known page compression algorithms.
+const (
+	NoCompression   = Compression("")
+	NoneCompression = Compression("none")
+	LZ4Compression  = Compression("lz4")
+	ZlibCompression = Compression("zlib")
+)
+
+// Enabled returns true if the page compression is enabled.
+func (c Compression) Enabled() bool {
+	return c == LZ4Compression || c == ZlibCompression
+}
+
+// String implements the fmt.Stringer interface.
+func (c Compression) String() string {
+	return string(c)
+}
+
+// InnoDB page sizes, in bytes.
+const (
+	// DefaultPageSize is the default InnoDB page size (innodb_page_size).
+	DefaultPageSize = 16384
+	// MinPageSize and MaxPageSize are the bounds of the InnoDB page size, a power of 2 between both.
+	MinPageSize = 4096
+	MaxPageSize = 65536
+	// DefaultKeyBlockSize is the compressed page size used by InnoDB, in KB,
+	// when the compressed row format is used without any KEY_BLOCK_SIZE.
+	DefaultKeyBlockSize = 8
+	// FilesystemBlockSize is the assumed size of a block on the filesystem.
+	FilesystemBlockSize = 4096
+)
+
+const kiloByte = 1024
+
+// ValidPageSize returns true if the size is a valid InnoDB page size: 4, 8, 16, 32 or 64 KB.
+func ValidPageSize(size uint64) bool {
+	return size >= MinPageSize && size <= MaxPageSize && size&(size-1) == 0
+}
+
+// pageSize returns the InnoDB page size of the table.
+func (t Table) pageSize() uint64 {
+	if t.PageSize == 0 {
+		return DefaultPageSize
+	}
+	return t.PageSize
+}
+
+func validKeyBlockSize(kbs uint64) bool {
+	switch kbs {
+	case 1, 2, 4, 8, 16:
+		return true
+	default:
+		return false
+	}
+}
+
+// CompressionRatio returns the assumed ratio between the compressed and the raw size of the data type.
+func (d DataType) CompressionRatio() float64 {
+	switch {
+	case d == JSON:
+		return 0.3
+	case d.IsSpatial():
+		// Coordinates are doubles, hardly compressible.
+		return 0.9
+	case d.IsVar():
+		return 0.5
+	case d.IsString():
+		return 0.6
+	default:
+		return 0.9
+	}
+}
+
+// On each record, the compressed page keeps uncompressed its entry in the dense page directory (2 bytes),
+// and for the modification log, the transaction ID (6 bytes) and the roll pointer (7 bytes).
+const compressedRecordOverhead = 2 + 6 + 7
+
+// compressionRatio returns the compression ratio to apply on the table pages:
+// the one forced by the configuration, otherwise the average of the ratio of each column weighted by its size.
+func (t Table) compressionRatio() float64 {
+	if t.CompressionRatio > 0 {
+		return math.Min(t.CompressionRatio, 1)
+	}
+	var sum, size float64
+	for _, c := range stored(t.Columns) {
+		_, x := c.Size()
+		sum += float64(x) * c.DataType.CompressionRatio()
+		size += float64(x)
+	}
+	if size == 0 {
+		return 1
+	}
+	return sum / size
+}
+
+// compressed returns true if the table uses the InnoDB compressed row format.
+func (t Table) compressed() bool {
+	return t.Engine == InnoDB && t.RowFormat == CompressedRowFormat
+}
+
+// compress returns the size of the data once compressed in a page of KEY_BLOCK_SIZE.
+// A compressed page can not contain more records than the uncompressed one,
+// so the compression ratio can not be better than the ratio between both page sizes.
+func (t Table) compress(size uint64) uint64 {
+	if size == ds.Unbounded {
+		return size
+	}
+	r := math.Max(t.compressionRatio(), float64(t.KeyBlockSize*kiloByte)/float64(t.pageSize()))
+	return uint64(math.Ceil(float64(size)*math.Min(r, 1))) + compressedRecordOverhead
+}
+
+// pageCompress returns the size of the data once the page compressed and the hole punched
+// by the filesystem, rounded to its block size.
+func (t Table) pageCompress(size uint64) uint64 {
+	if size == ds.Unbounded {
+		return size
+	}
+	var (
+		full  = float64(t.pageSize())
+		page  = math.Ceil(full*t.compressionRatio()/FilesystemBlockSize) * FilesystemBlockSize
+		ratio = math.Min(page, full) / full
+	)
+	return uint64(math.Ceil(float64(size) * ratio))
+}
+
+// Footprints returns the sizes of the table in the buffer pool or on the disk
+// when they differ from its size.
+func (t Table) Footprints() []ds.Data {
+	var res []ds.Data
+	if t.compressed() {
+		// The buffer pool keeps both the compressed page and its uncompressed copy.
+		min, max := t.rawSize()
+		n, x := t.Size()
+		res = append(res, footprint{
+			name: bufferPool,
+			kind: fmt.Sprintf("memory(%dK + %dK)", t.KeyBlockSize, t.pageSize()/kiloByte),
+			min:  ds.Add(min, n),
+			max:  ds.Add(max, x),
+		})
+	}
+	if t.Compression.Enabled() && !t.compressed() {
+		min, max := t.Size()
+		res = append(res, footprint{
+			name: onDisk,
+			kind: fmt.Sprintf("disk(%s, %dK blocks)", t.Compression, FilesystemBlockSize/kiloByte),
+			min:  t.pageCompress(min),
+			max:  t.pageCompress(max),
+		})
+	}
+	return res
+}
+
+const (
+	bufferPool = "buffer pool"
+	onDisk     = "on disk"
+)
+
+// footprint is a size of a table in a specific place.
+type footprint struct {
+	name, kind string
+	min, max   uint64
+}
+
+// Kind implements the ds.Data interface.
+func (f footprint) Kind() string {
+	return f.kind
+}
+
+// Size implements the ds.Data interface.
+func (f footprint) Size() (min, max uint64) {
+	return f.min, f.max
+}
+
+// String implements the ds.Data interface.
+func (f footprint) String() string {
+	return f.name
+}
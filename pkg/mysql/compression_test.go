@@ -0,0 +1,80 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/ds/render"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestEstimator_Parse_Compression(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			options   string
+			ratio     float64
+			size      uint64
+			footprint string
+			min, max  uint64
+			err       error
+		}{
+			"Default":         {size: 8},
+			"Compressed":      {options: "ROW_FORMAT=COMPRESSED", size: 23, footprint: "memory(8K + 16K)", min: 31, max: 31},
+			"CompressedRatio": {options: "ROW_FORMAT=COMPRESSED", ratio: 0.1, size: 19, footprint: "memory(8K + 16K)", min: 27, max: 27},
+			"KeyBlockSize":    {options: "KEY_BLOCK_SIZE=4", size: 23, footprint: "memory(4K + 16K)", min: 31, max: 31},
+			"KeyBlockRatio":   {options: "ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=4", ratio: 0.1, size: 17, footprint: "memory(4K + 16K)", min: 25, max: 25},
+			"Zlib":            {options: "COMPRESSION='zlib'", size: 8, footprint: "disk(zlib, 4K blocks)", min: 8, max: 8},
+			"ZlibRatio":       {options: "COMPRESSION='zlib'", ratio: 0.1, size: 8, footprint: "disk(zlib, 4K blocks)", min: 2, max: 2},
+			"LZ4":             {options: "COMPRESSION='lz4'", ratio: 0.5, size: 8, footprint: "disk(lz4, 4K blocks)", min: 4, max: 4},
+			"None":            {options: "COMPRESSION='none'", size: 8},
+			"BlockSize":       {options: "KEY_BLOCK_SIZE=3", err: ds.ErrInvalid},
+			"BlockSizeNaN":    {options: "KEY_BLOCK_SIZE=x", err: ds.ErrInvalid},
+			"Ratio":           {options: "ROW_FORMAT=COMPRESSED", ratio: 2, err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := parse("CREATE TABLE t (id INT NOT NULL, PRIMARY KEY (id)) "+tt.options, mysql.SetCompressionRatio(tt.ratio))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			min, max := dbs[0].Tables[0].Size()
+			are.Equal(tt.size, min) // mismatch minimum size
+			are.Equal(tt.size, max) // mismatch maximum size
+			fs := dbs[0].Tables[0].Footprints()
+			if tt.footprint == "" {
+				are.Equal(0, len(fs)) // unexpected footprint
+				return
+			}
+			are.Equal(1, len(fs))                 // mismatch footprints
+			are.Equal(tt.footprint, fs[0].Kind()) // mismatch footprint kind
+			min, max = fs[0].Size()
+			are.Equal(tt.min, min) // mismatch minimum footprint
+			are.Equal(tt.max, max) // mismatch maximum footprint
+		})
+	}
+}
+
+func TestEstimator_Render_Footprints(t *testing.T) {
+	are := is.New(t)
+	for _, verbose := range []bool{false, true} {
+		e, err := mysql.Estimate(mysql.SetFormat(render.CSVFormat), mysql.SetVerbose(verbose))
+		are.NoErr(err) // unexpected estimator error
+		dbs, err := e.Parse(strings.NewReader("CREATE TABLE t (id INT NOT NULL) ROW_FORMAT=COMPRESSED"))
+		are.NoErr(err) // unexpected parse error
+		buf := new(bytes.Buffer)
+		are.NoErr(e.Render(buf, dbs))                                     // unexpected render error
+		are.Equal(verbose, strings.Contains(buf.String(), "buffer pool")) // footprint only in verbose mode
+	}
+}
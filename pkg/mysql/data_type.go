@@ -7,6 +7,8 @@ package mysql
 import (
 	"math"
 	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
 )
 
 // ToDataType returns a MySQL DataType based on the given data name.
@@ -43,7 +45,7 @@ const (
 	TinyBlob   DataType = "tinyblob"
 	TinyText   DataType = "tinytext"
 	Blob       DataType = "blob"
-	Text       DataType = "test"
+	Text       DataType = "text"
 	MediumBlob DataType = "mediumblob"
 	MediumText DataType = "mediumtext"
 	LongBlob   DataType = "longblob"
@@ -51,6 +53,15 @@ const (
 	JSON       DataType = "json"
 	Enum       DataType = "enum"
 	Set        DataType = "set"
+	// Spatial data types.
+	Geometry           DataType = "geometry"
+	Point              DataType = "point"
+	LineString         DataType = "linestring"
+	Polygon            DataType = "polygon"
+	MultiPoint         DataType = "multipoint"
+	MultiLineString    DataType = "multilinestring"
+	MultiPolygon       DataType = "multipolygon"
+	GeometryCollection DataType = "geometrycollection"
 )
 
 // Kind implements the ds.Data interface.
@@ -69,6 +80,44 @@ func (d DataType) IsInt() bool {
 	}
 }
 
+// IsBinary returns true if the data type stores binary strings.
+func (d DataType) IsBinary() bool {
+	switch d {
+	case
+		Binary, VarBinary,
+		TinyBlob, MediumBlob, Blob, LongBlob:
+		return true
+	default:
+		return d.IsSpatial()
+	}
+}
+
+// IsSpatial returns true if the data type stores geometry values.
+func (d DataType) IsSpatial() bool {
+	switch d {
+	case
+		Geometry, Point, LineString, Polygon,
+		MultiPoint, MultiLineString, MultiPolygon, GeometryCollection:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsBlob returns true if the data type is a BLOB or TEXT one, stored outside of the row.
+// Spatial values are also stored as BLOB.
+func (d DataType) IsBlob() bool {
+	switch d {
+	case
+		TinyBlob, MediumBlob, Blob, LongBlob,
+		TinyText, MediumText, Text, LongText,
+		JSON:
+		return true
+	default:
+		return d.IsSpatial()
+	}
+}
+
 // IsString returns true if the data type is a string.
 func (d DataType) IsString() bool {
 	switch d {
@@ -95,7 +144,7 @@ func (d DataType) IsVar() bool {
 		JSON:
 		return true
 	default:
-		return false
+		return d.IsSpatial()
 	}
 }
 
@@ -149,8 +198,13 @@ func (d DataType) Size(size uint64, charset string) (min, max uint64) {
 		return enum(size)
 	case Set:
 		return set(size)
+	case
+		Geometry, Point, LineString, Polygon,
+		MultiPoint, MultiLineString, MultiPolygon, GeometryCollection:
+		// The size is the number of points by value.
+		return d.spatial(size)
 	default:
-		return 0, math.MaxUint64
+		return 0, ds.Unbounded
 	}
 }
 
@@ -161,7 +215,7 @@ func (d DataType) String() string {
 
 func blob(size, reserved, max uint64) (uint64, uint64) {
 	if size > 0 {
-		return reserved, size + reserved
+		return reserved, ds.Add(size, reserved)
 	}
 	return reserved, max - 1 + reserved
 }
@@ -175,7 +229,7 @@ func bytes(size uint64, charset string) uint64 {
 	if !set {
 		return 0
 	}
-	return size * uint64(char)
+	return ds.Mul(size, uint64(char))
 }
 
 func enum(size uint64) (uint64, uint64) {
@@ -218,7 +272,7 @@ func set(size uint64) (uint64, uint64) {
 
 func variable(size uint64) (uint64, uint64) {
 	if size > math.MaxUint8 {
-		return 2, size + 2
+		return 2, ds.Add(size, 2)
 	}
 	if size == 0 {
 		return 1, math.MaxUint8
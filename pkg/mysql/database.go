@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import "github.com/rvflash/ds/pkg/ds"
+
+// Database represents a database.
+type Database struct {
+	Name    string
+	Charset string
+	Tables  []Table
+}
+
+const db = "database"
+
+// Kind implements the ds.Data interface.
+func (d Database) Kind() string {
+	return db
+}
+
+// Size implements the ds.Data interface.
+func (d Database) Size() (min, max uint64) {
+	var n, x uint64
+	for _, c := range d.Tables {
+		n, x = c.Size()
+		min, max = ds.Add(min, n), ds.Add(max, x)
+	}
+	return
+}
+
+// Distribution implements the ds.Distributor interface.
+func (d Database) Distribution() ds.Distribution {
+	var res ds.Distribution
+	for _, t := range d.Tables {
+		res = res.Add(t.Distribution())
+	}
+	return res
+}
+
+// Scale implements the ds.Scaler interface.
+func (d Database) Scale(rows uint64) (min, max uint64) {
+	var n, x uint64
+	for _, c := range d.Tables {
+		n, x = c.Scale(rows)
+		min, max = ds.Add(min, n), ds.Add(max, x)
+	}
+	return
+}
+
+// Children implements the ds.Node interface.
+func (d Database) Children() []ds.Data {
+	res := make([]ds.Data, len(d.Tables))
+	for p, t := range d.Tables {
+		res[p] = t
+	}
+	return res
+}
+
+// String implements the ds.Data interface.
+func (d Database) String() string {
+	return d.Name
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Diagnostic describes an issue preventing to estimate the size of a column, like an unknown data type.
+type Diagnostic struct {
+	Database,
+	Table,
+	Column string
+	Err error
+}
+
+// Error implements the error interface.
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("%s.%s.%s: %s", d.Database, d.Table, d.Column, d.Err)
+}
+
+// Unwrap returns the underlying error.
+func (d Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Known returns true if the size of the data type can be estimated.
+func (d DataType) Known() bool {
+	switch d {
+	case
+		Bit, TinyInt, SmallInt, MediumInt, Int, Integer, BigInt,
+		Float, Double, Decimal, Numeric, Real,
+		Year, Date, Time, Timestamp, DateTime,
+		Char, Binary, VarChar, VarBinary,
+		TinyBlob, TinyText, Blob, Text, MediumBlob, MediumText, LongBlob, LongText,
+		JSON, Enum, Set:
+		return true
+	default:
+		return d.IsSpatial()
+	}
+}
+
+// diagnose checks the data type of each column, not already diagnosed.
+// Any column with an issue is excluded from the estimation.
+func (t *Table) diagnose() {
+	for p, c := range t.Columns {
+		if c.Excluded || c.DataType.Known() {
+			continue
+		}
+		t.Columns[p].Excluded = true
+		t.Diagnostics = append(t.Diagnostics, Diagnostic{
+			Table:  t.Name,
+			Column: c.Name,
+			Err:    ds.WrapErr("unknown data type "+c.DataType.String(), ds.ErrInvalid),
+		})
+	}
+}
+
+// placeholderDataType is the data type replacing an invalid one, to parse the column definition anyway.
+const placeholderDataType = "text"
+
+// invalidDataType checks the data type of the column definition, not supported by the SQL parser
+// if it is unknown or if its length is not a number, like frobnicate or varchar(abc).
+// In such a case, it returns the definition with a valid data type and the issue found, the column being excluded.
+func invalidDataType(def tokens) (tokens, DataType, error) {
+	pos := def.next(def.next(-1))
+	t := def.at(pos)
+	if t.typ != wordToken {
+		return def, "", nil
+	}
+	var (
+		err  error
+		args []tokens
+		end  = pos
+		typ  = ToDataType(t.val)
+		repl = lex(placeholderDataType)
+	)
+	if p := def.next(pos); def.at(p).is("(") {
+		args, end = def.list(p)
+	}
+	switch {
+	case !typ.Known():
+		err = ds.WrapErr("unknown data type "+typ.String(), ds.ErrInvalid)
+	case typ == Enum, typ == Set:
+		return def, "", nil
+	default:
+		for _, a := range args {
+			if _, err = strconv.ParseUint(strings.TrimSpace(a.String()), base10, bits64); err != nil {
+				s := strings.TrimSpace(def[def.next(pos)+1 : end].String())
+				err = ds.WrapErr("data length "+s, ds.ErrInvalid)
+				repl = lex(t.val + "(0)")
+				break
+			}
+		}
+	}
+	if err == nil {
+		return def, "", nil
+	}
+	res := append(append(tokens{}, def[:pos]...), repl...)
+	for _, t := range def[min(end+1, len(def)):] {
+		// The numeric attributes are not supported by the placeholder.
+		if t.is("signed") || t.is("unsigned") || t.is("zerofill") {
+			continue
+		}
+		res = append(res, t)
+	}
+	return res, typ, err
+}
+
+// Flagged implements the ds.Flagger interface.
+// An index is flagged when one of its key parts has been excluded from the estimation.
+func (i Index) Flagged() bool {
+	for _, k := range i.Parts {
+		if k.Excluded {
+			return true
+		}
+	}
+	return false
+}
+
+// Flagged implements the ds.Flagger interface.
+// A table is flagged when it has at least one diagnostic.
+func (t Table) Flagged() bool {
+	return len(t.Diagnostics) > 0
+}
+
+// Flagged implements the ds.Flagger interface.
+// A database is flagged when one of its tables is flagged.
+func (d Database) Flagged() bool {
+	for _, t := range d.Tables {
+		if t.Flagged() {
+			return true
+		}
+	}
+	return false
+}
+
+// Diagnostics returns the diagnostics of all the tables of the database.
+func (d Database) Diagnostics() []Diagnostic {
+	var res []Diagnostic
+	for _, t := range d.Tables {
+		for _, v := range t.Diagnostics {
+			v.Database = d.Name
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Diagnostics returns the diagnostics of all the databases.
+func (s Storage) Diagnostics() []Diagnostic {
+	var res []Diagnostic
+	for _, d := range s {
+		res = append(res, d.Diagnostics()...)
+	}
+	return res
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestEstimator_Parse_Diagnostics(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in     string
+			column string
+			msg    string
+		}{
+			"Unknown":  {in: "CREATE TABLE t (x FROBNICATE)", column: "x", msg: "unknown data type frobnicate"},
+			"Args":     {in: "CREATE TABLE t (x FROBNICATE(3) UNSIGNED NOT NULL)", column: "x", msg: "unknown data type frobnicate"},
+			"Length":   {in: "CREATE TABLE t (x VARCHAR(abc) NOT NULL)", column: "x", msg: "data length abc"},
+			"Decimal":  {in: "CREATE TABLE t (x DECIMAL(10, x))", column: "x", msg: "data length 10, x"},
+			"Alter":    {in: "CREATE TABLE t (id INT); ALTER TABLE t ADD COLUMN x frobnicate", column: "x", msg: "unknown data type frobnicate"},
+			"Overflow": {in: "CREATE TABLE t (x CHAR(99999999999999999999))", column: "x", msg: "data length 99999999999999999999"},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			// In lenient mode, the column is excluded and the diagnostic reported.
+			e, err := mysql.Estimate(mysql.SetDiagnosticOutput(buf))
+			are.NoErr(err) // unexpected estimator error
+			dbs, err := e.Parse(strings.NewReader(tt.in))
+			are.NoErr(err) // unexpected lenient error
+			diags := dbs.Diagnostics()
+			are.Equal(1, len(diags))                         // mismatch number of diagnostics
+			are.Equal("t", diags[0].Table)                   // mismatch table
+			are.Equal(tt.column, diags[0].Column)            // mismatch column
+			are.True(errors.Is(diags[0], ds.ErrInvalid))     // mismatch diagnostic error
+			are.True(strings.Contains(buf.String(), tt.msg)) // mismatch report
+			tb := dbs[0].Tables[0]
+			are.True(tb.Columns[len(tb.Columns)-1].Excluded) // expected excluded column
+			// In strict mode, the diagnostic is returned as error.
+			e, err = mysql.Estimate(mysql.SetStrictMode(true))
+			are.NoErr(err) // unexpected estimator error
+			_, err = e.Parse(strings.NewReader(tt.in))
+			var d mysql.Diagnostic
+			are.True(errors.As(err, &d))   // expected diagnostic
+			are.Equal(tt.column, d.Column) // mismatch strict column
+		})
+	}
+}
+
+// failWriter is a writer always in error.
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, ds.ErrProcess
+}
+
+func TestEstimator_Parse_DiagnosticOutput(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  string
+			err error
+		}{
+			"Known":   {in: "CREATE TABLE t (x INT)"},
+			"Unknown": {in: "CREATE TABLE t (x FROBNICATE)", err: ds.ErrProcess},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			// The writer is only used to report the diagnostics.
+			dbs, err := parse(tt.in, mysql.SetDiagnosticOutput(failWriter{}))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			are.Equal(0, len(dbs.Diagnostics())) // unexpected diagnostic
+		})
+	}
+}
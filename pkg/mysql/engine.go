@@ -5,6 +5,7 @@
 package mysql
 
 import (
+	"math"
 	"strings"
 
 	"github.com/rvflash/ds/pkg/ds"
@@ -33,10 +34,28 @@ const (
 )
 
 // Fields returns the columns with their sizes updated with engine and row format constrains.
-func (e Engine) Fields(cols []Column) []ds.Data {
-	res := make([]ds.Data, len(cols))
-	for p, c := range cols {
-		res[p] = c
+// The virtual generated columns are not stored in the rows, and the hidden ones are ignored.
+func (e Engine) Fields(cols []Column, cur RowFormat) []ds.Data {
+	res := make([]ds.Data, 0, len(cols))
+	for _, c := range e.nulls(cols, cur) {
+		switch {
+		case c.hidden():
+		case c.Generated.Virtual():
+			res = append(res, ds.NewDataSize(c, 0, 0))
+		default:
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// stored returns the columns whose values are stored in the rows.
+func stored(cols []Column) []Column {
+	res := make([]Column, 0, len(cols))
+	for _, c := range cols {
+		if !c.Generated.Virtual() {
+			res = append(res, c)
+		}
 	}
 	return res
 }
@@ -70,10 +89,11 @@ func innoDBKeys(keys []Index, primary int) []ds.Data {
 	pkn, pkx := pks()
 	for p, k := range keys {
 		n, x = k.Size()
-		if k.Primary {
+		if k.Primary || k.Type == FullTextIndex {
+			// The auxiliary tables of a FULLTEXT index refer to the document IDs, not to the primary key.
 			res[p] = k
 		} else {
-			res[p] = ds.NewDataSize(k, n+pkn, x+pkx)
+			res[p] = ds.NewDataSize(k, ds.Add(n, pkn), ds.Add(x, pkx))
 		}
 	}
 	return res
@@ -85,12 +105,21 @@ func myISAMKeys(keys []Index) []ds.Data {
 		n, x uint64
 		res  = make([]ds.Data, len(keys))
 		fml  = func(size uint64) uint64 {
+			if size == ds.Unbounded {
+				return size
+			}
 			i := (float64(size) + 4) / 0.67
+			if i >= math.MaxUint64 {
+				return ds.Unbounded
+			}
 			return uint64(i)
 		}
 	)
 	for p, c := range keys {
 		n, x = c.Size()
+		if c.Type == FullTextIndex {
+			n, x = c.myISAMFullTextSize()
+		}
 		res[p] = ds.NewDataSize(c, fml(n), fml(x))
 	}
 	return res
@@ -99,6 +128,7 @@ func myISAMKeys(keys []Index) []ds.Data {
 // RowFormat defines the row format to use based on columns or the current value.
 // sql: SELECT row_format FROM information_schema.tables WHERE table_schema="dbName" AND table_name="tbName";
 func (e Engine) RowFormat(cols []Column, cur RowFormat) RowFormat {
+	cols = stored(cols)
 	switch e {
 	case InnoDB:
 		return innoDBRowFormat(cur)
@@ -130,6 +160,7 @@ func myISAMRowFormat(cols []Column, cur RowFormat) RowFormat {
 
 // RowSize returns the estimates row length.
 func (e Engine) RowSize(cols []Column, cur RowFormat) (min, max uint64) {
+	cols = e.nulls(stored(cols), cur)
 	switch e {
 	case InnoDB:
 		return innoDBRowSize(cols, cur)
@@ -144,8 +175,7 @@ func innoDBRowSize(cols []Column, _ RowFormat) (min, max uint64) {
 	var n, x uint64
 	for _, c := range cols {
 		n, x = c.Size()
-		min += n
-		max += x
+		min, max = ds.Add(min, n), ds.Add(max, x)
 	}
 	return
 }
@@ -160,8 +190,7 @@ func myISAMRowSize(cols []Column, cur RowFormat) (min, max uint64) {
 	var n, x, nn, nv, ns, ni uint64
 	for _, c := range cols {
 		n, x = c.Size()
-		min += n
-		max += x
+		min, max = ds.Add(min, n), ds.Add(max, x)
 		if !c.NotNull {
 			nn++
 		}
@@ -184,7 +213,7 @@ func myISAMRowSize(cols []Column, cur RowFormat) (min, max uint64) {
 		// + (sum of column lengths)
 		// + (number of NULL columns + delete_flag + 7)/8
 		// + (number of variable-length columns)
-		return both(staticHeader + max + ((nn + staticDeleteFlag + 7) / 8) + nv)
+		return both(ds.Add(staticHeader, max, (nn+staticDeleteFlag+7)/8, nv))
 	case DynamicRowFormat:
 		// Formula:
 		// 3 as header
@@ -201,7 +230,46 @@ func myISAMRowSize(cols []Column, cur RowFormat) (min, max uint64) {
 		// Formula:
 		// 1 or 3 as header
 		// + data size
-		return staticHeader + min, dynamicHeader + max
+		return ds.Add(staticHeader, min), ds.Add(dynamicHeader, max)
+	default:
+		return both(0)
+	}
+}
+
+// InnoDB stores each partition in its own tablespace, starting with the pages used to manage
+// the file (FSP header, insert buffer bitmap, inode and serialized dictionary information),
+// and the root page of each index. Once grown, the tablespace is extended by extent of 1 MB,
+// or of 64 pages with the page sizes larger than 16 KB.
+const (
+	innoDBFilePages   = 4
+	innoDBExtentPages = 64
+	innoDBExtentSize  = 1024 * kiloByte
+)
+
+// MyISAM stores each partition in its own data and index files,
+// the index file starting by a header of 1 KB followed by the root block of each key.
+const myISAMBlockSize = 1024
+
+// PartitionOverhead returns the size used by a partition, regardless of its number of rows.
+// The page size is the InnoDB one, DefaultPageSize if zero.
+func (e Engine) PartitionOverhead(indexes int, pageSize uint64) (min, max uint64) {
+	switch e {
+	case InnoDB:
+		if indexes == 0 {
+			// Clustered index.
+			indexes = 1
+		}
+		if pageSize == 0 {
+			pageSize = DefaultPageSize
+		}
+		min = (innoDBFilePages + uint64(indexes)) * pageSize
+		extent := uint64(innoDBExtentSize)
+		if pageSize*innoDBExtentPages > extent {
+			extent = pageSize * innoDBExtentPages
+		}
+		return min, min + extent
+	case MyISAM:
+		return both(myISAMBlockSize * (1 + uint64(indexes)))
 	default:
 		return both(0)
 	}
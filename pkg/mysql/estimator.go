@@ -0,0 +1,581 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"text/template"
+	"time"
+
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/ds/render"
+)
+
+// Default values used to configure the estimator.
+const (
+	Command          = "mysql"
+	DefaultPerN      = render.DefaultPerN
+	DefaultPrecision = render.DefaultPrecision
+)
+
+// Config lists any customizable settings.
+// The yaml tags are the keys of the settings in the configuration file, see ds.ConfigFile.
+type Config struct {
+	Batch              bool    `yaml:"batch"`
+	Expected           bool    `yaml:"expected"`
+	Strict             bool    `yaml:"strict"`
+	Verbose            bool    `yaml:"verbose"`
+	Precision          uint64  `yaml:"precision"`
+	FullTextWords      uint64  `yaml:"fulltext_words"`
+	FullTextWordLength uint64  `yaml:"fulltext_word_length"`
+	Vertices           uint64  `yaml:"vertices"`
+	CompressionRatio   float64 `yaml:"compression_ratio"`
+	// PerN lists the numbers of rows to estimate, each one with its own sizes.
+	PerN ds.Scenarios `yaml:"rows"`
+	// Columns lists the comma separated columns of the report: type, row, n, engine, charset or nullable.
+	Columns string `yaml:"columns"`
+	// Format is the output format: table, tree, csv, json, markdown or html.
+	Format string `yaml:"format"`
+	// Units is the system of units, si or iec, or the symbol of the unit forced for all the sizes, like GiB.
+	Units string `yaml:"units"`
+	// Order is the order of the tables, and in verbose mode, of the columns and keys: min or max size.
+	Order string `yaml:"order"`
+	// Top is the number of largest tables to report by database, zero meaning all of them.
+	Top uint64 `yaml:"top"`
+	// Database, Table and Engine filter the tables to report: the names must match the shell patterns,
+	// like shop_*, and the engine, the given one.
+	Database string `yaml:"database"`
+	Table    string `yaml:"table"`
+	Engine   string `yaml:"engine"`
+	// Charset and DefaultEngine are used for the databases and the tables declared without them.
+	Charset       string `yaml:"charset"`
+	DefaultEngine string `yaml:"default_engine"`
+	// PageSize is the InnoDB page size, like 32KiB, DefaultPageSize if zero.
+	PageSize ds.Unit `yaml:"page_size"`
+	// Template is the path of the Go text template used to render the report, overloading the format.
+	Template string `yaml:"template"`
+	// Profiles is the path of the file describing the profiles of the columns.
+	Profiles string `yaml:"profiles"`
+	// Interactive enables the interactive session, see Estimator.Interact. It is only available as flag.
+	Interactive bool `yaml:"-"`
+	// WatchMode enables the watch of the files, see Estimator.Watch. It is only available as flag.
+	WatchMode bool `yaml:"-"`
+	// WatchInterval is the interval between two polls of the watched files, like 1s.
+	WatchInterval time.Duration `yaml:"watch_interval"`
+}
+
+// Configurator is implemented by any method exposing cursor to adjust the estimator.
+type Configurator func(*Estimator) error
+
+// SetPerN defines the number of data to take account in the estimation.
+func SetPerN(i uint64) Configurator {
+	return func(e *Estimator) error {
+		if i == 0 {
+			return ds.WrapErr("per N value", ds.ErrMissing)
+		}
+		e.perN = i
+		e.scenarios = []uint64{i}
+		return nil
+	}
+}
+
+// SetScenarios defines the numbers of rows to take account in the estimation, each one with its own sizes,
+// like the current one, the one expected next year and the one at full scale.
+// The first one is used to sort the tables and by the outputs with a single number of rows, like the tree.
+func SetScenarios(rows ...uint64) Configurator {
+	return func(e *Estimator) error {
+		if len(rows) == 0 {
+			return ds.WrapErr("scenarios", ds.ErrMissing)
+		}
+		for _, i := range rows {
+			if i == 0 {
+				return ds.WrapErr("scenario value", ds.ErrMissing)
+			}
+		}
+		e.perN = rows[0]
+		e.scenarios = rows
+		return nil
+	}
+}
+
+// SetColumns defines the columns to display after the name of the data, the default ones if none.
+// See Columns for the available ones.
+func SetColumns(cols ...render.Column) Configurator {
+	return func(e *Estimator) error {
+		for _, c := range cols {
+			if !validColumn(c) {
+				return ds.WrapErr("column "+c.String(), ds.ErrInvalid)
+			}
+		}
+		e.columns = cols
+		return nil
+	}
+}
+
+func validColumn(c render.Column) bool {
+	for _, v := range Columns() {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPrecision defines the decimal precision used to print data size.
+func SetPrecision(i uint64) Configurator {
+	return func(e *Estimator) error {
+		if i > math.MaxUint8 {
+			return ds.WrapErr("precision", ds.ErrInvalid)
+		}
+		e.precision = uint8(i)
+		return nil
+	}
+}
+
+// SetCompressionRatio defines the ratio between the compressed and the raw sizes of the data
+// used to estimate the size of the compressed tables.
+// If zero, the ratio is assumed for each table based on its columns data types.
+func SetCompressionRatio(r float64) Configurator {
+	return func(e *Estimator) error {
+		if r < 0 || r > 1 {
+			return ds.WrapErr("compression ratio", ds.ErrInvalid)
+		}
+		e.compressionRatio = r
+		return nil
+	}
+}
+
+// SetFullTextWords defines the average number of distinct words by row in the FULLTEXT indexes,
+// used if not declared on the index.
+func SetFullTextWords(i uint64) Configurator {
+	return func(e *Estimator) error {
+		e.fullTextWords = i
+		return nil
+	}
+}
+
+// SetFullTextWordLength defines the average number of characters by word in the FULLTEXT indexes,
+// used if not declared on the index.
+func SetFullTextWordLength(i uint64) Configurator {
+	return func(e *Estimator) error {
+		e.fullTextWordLength = i
+		return nil
+	}
+}
+
+// SetVertices defines the number of points by value of the spatial columns,
+// used if not declared on the column.
+func SetVertices(i uint64) Configurator {
+	return func(e *Estimator) error {
+		e.vertices = i
+		return nil
+	}
+}
+
+// SetVerbose defines the verbose mode to use to print the report.
+func SetVerbose(verbose bool) Configurator {
+	return func(e *Estimator) error {
+		e.verbose = verbose
+		return nil
+	}
+}
+
+// SetExpected defines if the expected sizes must be displayed next to the minimum and maximum ones.
+func SetExpected(enabled bool) Configurator {
+	return func(e *Estimator) error {
+		e.expected = enabled
+		return nil
+	}
+}
+
+// SetBinaryUnits defines if the sizes must be printed with the binary units defined by the IEC, like KiB,
+// instead of the decimal ones, like KB.
+func SetBinaryUnits(enabled bool) Configurator {
+	return func(e *Estimator) error {
+		e.binary = enabled
+		return nil
+	}
+}
+
+// SetUnit forces the unit used to print all the sizes, to make them comparable.
+// If zero, the most suited unit is used by size.
+func SetUnit(u ds.Unit) Configurator {
+	return func(e *Estimator) error {
+		if u != 0 && u.Symbol() == "" {
+			return ds.WrapErr("unit", ds.ErrInvalid)
+		}
+		e.unit = u
+		return nil
+	}
+}
+
+// SetOrder defines the order of the tables in each database, and in verbose mode,
+// of the columns and keys in each table, by descending size.
+func SetOrder(o ds.Order) Configurator {
+	return func(e *Estimator) error {
+		_, err := ds.ToOrder(string(o))
+		if err != nil {
+			return err
+		}
+		e.order = o
+		return nil
+	}
+}
+
+// SetTop limits the report to the N largest tables of each database, zero meaning all of them.
+// The totals of the databases only account for the reported tables.
+func SetTop(n uint64) Configurator {
+	return func(e *Estimator) error {
+		e.top = n
+		return nil
+	}
+}
+
+// SetDatabaseFilter limits the report to the databases whose name matches the shell pattern, like shop_*.
+func SetDatabaseFilter(pattern string) Configurator {
+	return func(e *Estimator) error {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return ds.WrapErr("database pattern", ds.ErrInvalid)
+		}
+		e.databasePattern = pattern
+		return nil
+	}
+}
+
+// SetTableFilter limits the report to the tables whose name matches the shell pattern, like log_*.
+// The totals of the databases only account for the reported tables.
+func SetTableFilter(pattern string) Configurator {
+	return func(e *Estimator) error {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return ds.WrapErr("table pattern", ds.ErrInvalid)
+		}
+		e.tablePattern = pattern
+		return nil
+	}
+}
+
+// SetEngineFilter limits the report to the tables using this engine, an empty one meaning any engine.
+func SetEngineFilter(engine Engine) Configurator {
+	return func(e *Estimator) error {
+		switch engine {
+		case "", InnoDB, MyISAM:
+			e.engine = engine
+			return nil
+		default:
+			return ds.WrapErr("engine", ds.ErrInvalid)
+		}
+	}
+}
+
+// SetDefaultCharset defines the charset of the databases declared without charset, DefaultCharset if empty.
+func SetDefaultCharset(charset string) Configurator {
+	return func(e *Estimator) error {
+		if _, ok := charsets[Charset(charset)]; !ok {
+			return ds.WrapErr("charset "+charset, ds.ErrInvalid)
+		}
+		e.charset = charset
+		return nil
+	}
+}
+
+// SetDefaultEngine defines the engine of the tables declared without engine, InnoDB if empty.
+func SetDefaultEngine(engine Engine) Configurator {
+	return func(e *Estimator) error {
+		switch engine {
+		case "", InnoDB, MyISAM:
+			e.defaultEngine = engine
+			return nil
+		default:
+			return ds.WrapErr("default engine", ds.ErrInvalid)
+		}
+	}
+}
+
+// SetPageSize defines the InnoDB page size in bytes (innodb_page_size), DefaultPageSize if zero.
+func SetPageSize(size uint64) Configurator {
+	return func(e *Estimator) error {
+		if size > 0 && !ValidPageSize(size) {
+			return ds.WrapErr("page size", ds.ErrInvalid)
+		}
+		e.pageSize = size
+		return nil
+	}
+}
+
+// SetWatchInterval defines the interval between two polls of the watched files.
+func SetWatchInterval(d time.Duration) Configurator {
+	return func(e *Estimator) error {
+		if d <= 0 {
+			return ds.WrapErr("watch interval", ds.ErrInvalid)
+		}
+		e.watchInterval = d
+		return nil
+	}
+}
+
+// SetProfiles defines the profiles of the columns, overloading the ones declared in their comments.
+func SetProfiles(p Profiles) Configurator {
+	return func(e *Estimator) error {
+		e.profiles = p
+		return nil
+	}
+}
+
+// SetStrictMode defines if the estimation must fail on any diagnostic, like an unknown data type,
+// or on any statement that can not be parsed. Otherwise, the columns with a diagnostic are excluded
+// from the totals and the affected rows are flagged, and the statements in error are skipped.
+func SetStrictMode(enabled bool) Configurator {
+	return func(e *Estimator) error {
+		e.strict = enabled
+		return nil
+	}
+}
+
+// SetDiagnosticOutput defines the writer used to report the diagnostics, if any.
+func SetDiagnosticOutput(w io.Writer) Configurator {
+	return func(e *Estimator) error {
+		e.diagnostics = w
+		return nil
+	}
+}
+
+// SetBatchMode defines if the batch mode must be used to export the report in CSV format.
+func SetBatchMode(enabled bool) Configurator {
+	return func(e *Estimator) error {
+		if enabled {
+			e.format = render.CSVFormat
+		}
+		return nil
+	}
+}
+
+// SetFormat defines the output format of the report.
+func SetFormat(f render.Format) Configurator {
+	return func(e *Estimator) error {
+		switch f {
+		case render.TableFormat, render.TreeFormat, render.CSVFormat, render.JSONFormat,
+			render.MarkdownFormat, render.HTMLFormat:
+			e.format = f
+			return nil
+		default:
+			return ds.WrapErr("format", ds.ErrInvalid)
+		}
+	}
+}
+
+// SetTemplate defines the user-defined template used to render the report, overloading the format.
+// It is executed with a render.Report whose data are the databases, with their tables, columns and indexes.
+// The template must be parsed with the functions of render.Funcs, like with render.ParseTemplateFile.
+func SetTemplate(t *template.Template) Configurator {
+	return func(e *Estimator) error {
+		e.template = t
+		return nil
+	}
+}
+
+// Estimate tries to instantiate a new estimator based on this configuration.
+func Estimate(opts ...Configurator) (*Estimator, error) {
+	opts = append([]Configurator{
+		SetFormat(render.TableFormat),
+		SetPerN(DefaultPerN),
+		SetPrecision(DefaultPrecision),
+		SetWatchInterval(DefaultWatchInterval),
+	}, opts...)
+	cnf := new(Estimator)
+	for _, opt := range opts {
+		err := opt(cnf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cnf, nil
+}
+
+// Estimator represents an MySQL data estimator.
+type Estimator struct {
+	diagnostics io.Writer
+	profiles    Profiles
+	format      render.Format
+	columns     []render.Column
+	template    *template.Template
+	order       ds.Order
+	engine,
+	defaultEngine Engine
+	charset,
+	databasePattern,
+	tablePattern string
+	binary,
+	expected,
+	strict,
+	verbose bool
+	precision uint8
+	perN,
+	top,
+	fullTextWords,
+	fullTextWordLength,
+	vertices,
+	pageSize uint64
+	compressionRatio float64
+	unit             ds.Unit
+	scenarios        []uint64
+	watchInterval    time.Duration
+}
+
+// Run runs the estimator: it parses the SQL statements and renders the estimation in the writer.
+func (e *Estimator) Run(r io.Reader, w io.Writer) error {
+	return e.RunContext(context.Background(), r, w)
+}
+
+// RunContext is like Run, but the parsing stops with the error of the context once done.
+func (e *Estimator) RunContext(ctx context.Context, r io.Reader, w io.Writer) error {
+	dbs, err := e.ParseContext(ctx, r)
+	if err != nil {
+		return err
+	}
+	return e.Render(w, dbs)
+}
+
+// Parse parses the SQL statements and applies the estimator settings on the resulting storage.
+// The default charset and engine are used for the databases and tables declared without them.
+// The sizes can then be computed in-process with the methods of the ds package, like ds.Scale or ds.ExpectN.
+func (e *Estimator) Parse(r io.Reader) (Storage, error) {
+	return e.ParseContext(context.Background(), r)
+}
+
+// ParseContext is like Parse, but it stops with the error of the context once done, checked between statements.
+func (e *Estimator) ParseContext(ctx context.Context, r io.Reader) (Storage, error) {
+	dbs, err := parse(ctx, r, defaults{charset: e.charset, engine: e.defaultEngine}, e.skip)
+	if err != nil {
+		return nil, err
+	}
+	if len(dbs) == 0 {
+		return nil, ds.ErrMissing
+	}
+	err = e.Apply(dbs)
+	if err != nil {
+		return nil, err
+	}
+	return dbs, nil
+}
+
+// Apply applies the estimator settings on the storage, parsed or built programmatically,
+// like the profiles of the columns or the compression ratio. The diagnostics are reported,
+// and in strict mode, the first of them is returned as error.
+func (e *Estimator) Apply(dbs Storage) error {
+	err := e.diagnose(dbs)
+	if err != nil {
+		return err
+	}
+	e.tune(dbs)
+	return nil
+}
+
+// Render renders the estimation of the storage in the writer.
+// The tables are filtered, sorted and limited to the top N of each database, if requested.
+func (e *Estimator) Render(w io.Writer, dbs Storage) error {
+	if e.perN == 0 {
+		return ds.ErrProcess
+	}
+	r, err := render.New(
+		render.SetFormat(e.format),
+		render.SetScenarios(e.scenarios...),
+		render.SetColumns(e.columns...),
+		render.SetPrecision(uint64(e.precision)),
+		render.SetVerbose(e.verbose),
+		render.SetExpected(e.expected),
+		render.SetBinaryUnits(e.binary),
+		render.SetUnit(e.unit),
+		render.SetTemplate(e.template),
+	)
+	if err != nil {
+		return err
+	}
+	return r.Render(w, e.selection(dbs)...)
+}
+
+// diagnose reports the diagnostics of the storage.
+// In strict mode, the first of them is returned as error.
+func (e *Estimator) diagnose(dbs Storage) error {
+	diags := dbs.Diagnostics()
+	if len(diags) == 0 {
+		return nil
+	}
+	if e.strict {
+		return diags[0]
+	}
+	if e.diagnostics == nil {
+		return nil
+	}
+	for _, d := range diags {
+		_, err := fmt.Fprintf(e.diagnostics, "%s: %s (excluded)\n", diagnostic, d)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const diagnostic = "warning"
+
+// skip reports the statement skipped because of its syntax error.
+// In strict mode, the error is returned instead.
+func (e *Estimator) skip(err error) error {
+	if e.strict {
+		return err
+	}
+	if e.diagnostics == nil {
+		return nil
+	}
+	_, err = fmt.Fprintf(e.diagnostics, "%s: %s (skipped)\n", diagnostic, err)
+	return err
+}
+
+// tune applies the estimator settings on each table.
+func (e *Estimator) tune(dbs Storage) {
+	for p := range dbs {
+		for i := range dbs[p].Tables {
+			t := &dbs[p].Tables[i]
+			for _, c := range t.Columns {
+				if v, ok := e.profiles.profile(dbs[p].Name, t.Name, c.Name); ok {
+					t.setProfile(c.Name, v)
+				}
+			}
+			if e.compressionRatio > 0 {
+				t.CompressionRatio = e.compressionRatio
+			}
+			if e.pageSize > 0 {
+				t.PageSize = e.pageSize
+			}
+			for c := range t.Columns {
+				if t.Columns[c].Vertices == 0 && t.Columns[c].DataType.IsSpatial() {
+					t.Columns[c].Vertices = e.vertices
+				}
+			}
+			for k := range t.Indexes {
+				if t.Indexes[k].Type != FullTextIndex {
+					continue
+				}
+				if t.Indexes[k].Words == 0 {
+					t.Indexes[k].Words = e.fullTextWords
+				}
+				if t.Indexes[k].WordLength == 0 {
+					t.Indexes[k].WordLength = e.fullTextWordLength
+				}
+			}
+		}
+	}
+}
+
+const (
+	base10 = 10
+	bits32 = 32
+	bits64 = 64
+)
@@ -0,0 +1,313 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/ds/render"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+const schema = `
+CREATE DATABASE shop;
+CREATE TABLE small (id TINYINT NOT NULL) ENGINE=MyISAM;
+CREATE TABLE large (id BIGINT NOT NULL, name VARCHAR(200), code CHAR(2) NOT NULL, KEY code (code));
+CREATE TABLE medium (id INT NOT NULL, name VARCHAR(20) NOT NULL);
+CREATE DATABASE blog;
+CREATE TABLE post (id INT NOT NULL);
+`
+
+// parse parses the SQL statements with an estimator using these options.
+func parse(in string, opts ...mysql.Configurator) (mysql.Storage, error) {
+	e, err := mysql.Estimate(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return e.Parse(strings.NewReader(in))
+}
+
+func TestEstimator_Render(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			opts []mysql.Configurator
+			out  []string
+		}{
+			"Default": {out: []string{"small", "large", "medium", "shop", "post", "blog"}},
+			"Sort":    {opts: []mysql.Configurator{mysql.SetOrder(ds.ByMinSize)}, out: []string{"large", "medium", "small", "shop", "post", "blog"}},
+			"Top":     {opts: []mysql.Configurator{mysql.SetTop(1)}, out: []string{"large", "shop", "post", "blog"}},
+			"Database": {
+				opts: []mysql.Configurator{mysql.SetDatabaseFilter("b*")},
+				out:  []string{"post", "blog"},
+			},
+			"Table": {
+				opts: []mysql.Configurator{mysql.SetTableFilter("*m*")},
+				out:  []string{"small", "medium", "shop"},
+			},
+			"Engine": {
+				opts: []mysql.Configurator{mysql.SetEngineFilter(mysql.MyISAM)},
+				out:  []string{"small", "shop"},
+			},
+			"Verbose": {
+				opts: []mysql.Configurator{mysql.SetVerbose(true), mysql.SetOrder(ds.ByMaxSize), mysql.SetTableFilter("large")},
+				out:  []string{"name", "id", "code", "code", "large", "", "shop"},
+			},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			e, err := mysql.Estimate(append(tt.opts, mysql.SetFormat(render.CSVFormat))...)
+			are.NoErr(err) // unexpected estimator error
+			buf := new(bytes.Buffer)
+			err = e.Run(strings.NewReader(schema), buf)
+			are.NoErr(err) // unexpected run error
+			rows, err := csv.NewReader(buf).ReadAll()
+			are.NoErr(err) // unexpected CSV error
+			names := make([]string, 0, len(rows))
+			for _, row := range rows[1:] {
+				names = append(names, row[0])
+			}
+			are.Equal(tt.out, names) // mismatch rows
+		})
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			opt mysql.Configurator
+			err error
+		}{
+			"Order":    {opt: mysql.SetOrder("name"), err: ds.ErrInvalid},
+			"Database": {opt: mysql.SetDatabaseFilter("["), err: ds.ErrInvalid},
+			"Table":    {opt: mysql.SetTableFilter("["), err: ds.ErrInvalid},
+			"Engine":   {opt: mysql.SetEngineFilter("Memory"), err: ds.ErrInvalid},
+			"Columns":  {opt: mysql.SetColumns(render.TypeColumn, "color"), err: ds.ErrInvalid},
+			"Scenario": {opt: mysql.SetScenarios(), err: ds.ErrMissing},
+			"Charset":  {opt: mysql.SetDefaultCharset("klingon"), err: ds.ErrInvalid},
+			"Default":  {opt: mysql.SetDefaultEngine("Memory"), err: ds.ErrInvalid},
+			"PageSize": {opt: mysql.SetPageSize(10000), err: ds.ErrInvalid},
+			"OK":       {opt: mysql.SetEngineFilter(mysql.InnoDB)},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			_, err := mysql.Estimate(tt.opt)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+		})
+	}
+}
+
+func TestEstimator_Render_Template(t *testing.T) {
+	are := is.New(t)
+	tpl, err := render.ParseTemplateFile("../../testdata/template/terraform.tfvars.tmpl")
+	are.NoErr(err) // unexpected parse error
+	e, err := mysql.Estimate(mysql.SetTemplate(tpl), mysql.SetTableFilter("*m*"), mysql.SetOrder(ds.ByMaxSize))
+	are.NoErr(err) // unexpected estimator error
+	buf := new(bytes.Buffer)
+	err = e.Run(strings.NewReader(schema), buf)
+	are.NoErr(err) // unexpected run error
+	out := "# Estimated sizes in bytes for 100 rows by table.\nshop_medium_size = 8500\nshop_small_size = 300\n"
+	are.Equal(out, buf.String()) // mismatch output
+}
+
+func TestEstimator_Render_Columns(t *testing.T) {
+	are := is.New(t)
+	e, err := mysql.Estimate(
+		mysql.SetFormat(render.CSVFormat),
+		mysql.SetVerbose(true),
+		mysql.SetTableFilter("large"),
+		mysql.SetScenarios(1, 1e6),
+		mysql.SetPrecision(0),
+		mysql.SetColumns(render.PerNColumn, mysql.EngineProperty, mysql.CharsetProperty, mysql.NullableProperty),
+	)
+	are.NoErr(err) // unexpected estimator error
+	buf := new(bytes.Buffer)
+	err = e.Run(strings.NewReader(schema), buf)
+	are.NoErr(err) // unexpected run error
+	rows, err := csv.NewReader(buf).ReadAll()
+	are.NoErr(err) // unexpected CSV error
+	are.Equal([]string{"Data", "X 1 (min)", "X 1 (max)", "X 1000000 (min)", "X 1000000 (max)", "Engine", "Charset", "Nullable"}, rows[0])
+	are.Equal([]string{"name", "2 B", "802 B", "2 MB", "802 MB", "", "utf8mb4", "YES"}, rows[2])       // mismatch column
+	are.Equal([]string{"large", "20 B", "820 B", "20 MB", "820 MB", "InnoDB", "utf8mb4", ""}, rows[5]) // mismatch table
+}
+
+func TestEstimator_Parse(t *testing.T) {
+	are := is.New(t)
+	e, err := mysql.Estimate(
+		mysql.SetDefaultCharset("LATIN1"),
+		mysql.SetDefaultEngine(mysql.MyISAM),
+		mysql.SetPageSize(32768),
+	)
+	are.NoErr(err) // unexpected estimator error
+	dbs, err := e.Parse(strings.NewReader(schema + "CREATE DATABASE news;\nCREATE TABLE a (b CHAR(2) CHARACTER SET ascii);"))
+	are.NoErr(err)                                           // unexpected parse error
+	are.Equal(3, len(dbs))                                   // mismatch databases
+	are.Equal("latin1", dbs[0].Charset)                      // mismatch default charset
+	are.Equal(mysql.MyISAM, dbs[0].Tables[1].Engine)         // mismatch default engine
+	are.Equal(uint64(32768), dbs[0].Tables[1].PageSize)      // mismatch page size
+	are.Equal("latin1", dbs[0].Tables[1].Columns[1].Charset) // mismatch column charset
+	are.Equal("ascii", dbs[2].Tables[0].Columns[0].Charset)  // mismatch declared charset
+}
+
+func TestEstimator_Parse_Alter(t *testing.T) {
+	const table = "CREATE TABLE t (id INT NOT NULL, name VARCHAR(20) NOT NULL, PRIMARY KEY (id));\n"
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in     string
+			tables []string
+			cols   []string
+			keys   []string
+			err    error
+		}{
+			"Default": {tables: []string{"t"}, cols: []string{"id", "name"}, keys: []string{"PRIMARY"}},
+			"Add": {
+				in:     "ALTER TABLE t ADD COLUMN age TINYINT AFTER id, ADD (a INT, b INT), ADD KEY (a, b)",
+				tables: []string{"t"}, cols: []string{"id", "age", "name", "a", "b"}, keys: []string{"PRIMARY", "a"},
+			},
+			"Drop": {
+				in:     "ALTER TABLE t DROP COLUMN id",
+				tables: []string{"t"}, cols: []string{"name"},
+			},
+			"Modify": {
+				in:     "ALTER TABLE t MODIFY name VARCHAR(50) NOT NULL FIRST",
+				tables: []string{"t"}, cols: []string{"name", "id"}, keys: []string{"PRIMARY"},
+			},
+			"Change": {
+				in:     "ALTER TABLE t CHANGE name title CHAR(10), ADD UNIQUE (title); ALTER TABLE t RENAME COLUMN id TO uid",
+				tables: []string{"t"}, cols: []string{"uid", "title"}, keys: []string{"PRIMARY", "title"},
+			},
+			"Index": {
+				in:     "CREATE UNIQUE INDEX n ON t (name); DROP INDEX n ON t; ALTER TABLE t DROP PRIMARY KEY",
+				tables: []string{"t"}, cols: []string{"id", "name"},
+			},
+			"Rename": {
+				in:     "RENAME TABLE t TO u; ALTER TABLE u RENAME TO v",
+				tables: []string{"v"}, cols: []string{"id", "name"}, keys: []string{"PRIMARY"},
+			},
+			"Replace": {
+				in:     "CREATE TABLE t (id INT); CREATE TABLE IF NOT EXISTS t (id INT, other INT)",
+				tables: []string{"t"}, cols: []string{"id"},
+			},
+			"DropTable":  {in: "DROP TABLE IF EXISTS x, t"},
+			"Unknown":    {in: "DROP TABLE x", err: ds.ErrInvalid},
+			"Column":     {in: "ALTER TABLE t DROP COLUMN x", err: ds.ErrInvalid},
+			"Definition": {in: "ALTER TABLE t ADD COLUMN ()", err: ds.ErrMissing},
+			"Using": {
+				in:     "ALTER TABLE t ADD KEY k USING BTREE (id), ADD UNIQUE USING HASH (name); CREATE INDEX i USING BTREE ON t (id)",
+				tables: []string{"t"}, cols: []string{"id", "name"}, keys: []string{"PRIMARY", "k", "name", "i"},
+			},
+			"RenameAs": {
+				in:     "ALTER TABLE t ADD a INT, RENAME AS unknown.u",
+				tables: []string{"u"}, cols: []string{"id", "name", "a"}, keys: []string{"PRIMARY"},
+			},
+			"Exists": {in: "CREATE TABLE u (id INT); ALTER TABLE t ADD a INT, RENAME TO u", err: ds.ErrInvalid},
+			"Syntax": {in: "ALTER TABLE t RENAME COLUMN id uid", err: ds.ErrInvalid},
+		}
+	)
+	e, err := mysql.Estimate()
+	are.NoErr(err) // unexpected estimator error
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := e.Parse(strings.NewReader(table + tt.in))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if err != nil {
+				return
+			}
+			var tables, cols, keys []string
+			for _, t := range dbs[0].Tables {
+				tables = append(tables, t.Name)
+				for _, c := range t.Columns {
+					cols = append(cols, c.Name)
+				}
+				for _, k := range t.Indexes {
+					keys = append(keys, k.Name)
+				}
+			}
+			are.Equal(tt.tables, tables) // mismatch tables
+			are.Equal(tt.cols, cols)     // mismatch columns
+			are.Equal(tt.keys, keys)     // mismatch keys
+		})
+	}
+}
+
+func TestEstimator_Parse_Charset(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in      string
+			charset string
+			engine  mysql.Engine
+			// column is the charset of the last column of the table.
+			column string
+		}{
+			"Default":  {in: "CREATE TABLE t (c CHAR(3))", charset: "utf8mb4", column: "utf8mb4"},
+			"Database": {in: "CREATE DATABASE d CHARACTER SET latin1; CREATE TABLE t (c CHAR(3))", charset: "latin1", column: "latin1"},
+			"Table": {
+				in:      "CREATE DATABASE d CHARACTER SET utf8; CREATE TABLE t (c CHAR(3)) ENGINE=InnoDB DEFAULT CHARSET=latin1",
+				charset: "latin1", column: "latin1",
+			},
+			"CharacterSet": {in: "CREATE TABLE t (c CHAR(3)) DEFAULT CHARACTER SET = latin1", charset: "latin1", column: "latin1"},
+			"Spaces": {
+				in:      "CREATE TABLE t (c CHAR(3)) CHARSET LATIN1 ENGINE MyISAM COLLATE latin1_bin",
+				charset: "latin1", engine: mysql.MyISAM, column: "latin1",
+			},
+			"Quoted": {
+				in:      "CREATE TABLE t (c CHAR(3)) ENGINE = MyISAM, DEFAULT CHARSET = 'latin1'",
+				charset: "latin1", engine: mysql.MyISAM, column: "latin1",
+			},
+			"Column": {in: "CREATE TABLE t (c CHAR(3) CHARACTER SET ascii) DEFAULT CHARSET=latin1", charset: "latin1", column: "ascii"},
+			"Alter": {
+				in:      "CREATE TABLE t (c CHAR(3)) DEFAULT CHARSET=latin1; ALTER TABLE t ADD COLUMN d CHAR(3)",
+				charset: "latin1", column: "latin1",
+			},
+			"AlterCharset": {
+				in:      "CREATE TABLE t (c CHAR(3)) DEFAULT CHARSET=latin1; ALTER TABLE t CHARSET=ascii, ADD COLUMN d CHAR(3)",
+				charset: "ascii", column: "ascii",
+			},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := mysql.Parse(strings.NewReader(tt.in))
+			are.NoErr(err) // unexpected error
+			tb := dbs[0].Tables[0]
+			are.Equal(tt.charset, tb.Charset)                           // mismatch table charset
+			are.Equal(tt.column, tb.Columns[len(tb.Columns)-1].Charset) // mismatch column charset
+			if tt.engine != "" {
+				are.Equal(tt.engine, tb.Engine) // mismatch engine
+			}
+		})
+	}
+}
+
+func TestEstimator_ParseContext(t *testing.T) {
+	are := is.New(t)
+	e, err := mysql.Estimate()
+	are.NoErr(err) // unexpected estimator error
+	ctx, cancel := context.WithCancel(context.Background())
+	dbs, err := e.ParseContext(ctx, strings.NewReader("CREATE TABLE t (id INT)"))
+	are.NoErr(err)         // unexpected error
+	are.Equal(1, len(dbs)) // mismatch databases
+	cancel()
+	_, err = e.ParseContext(ctx, strings.NewReader("CREATE TABLE t (id INT)"))
+	are.True(errors.Is(err, context.Canceled)) // expected canceled error
+	err = e.RunContext(ctx, strings.NewReader("CREATE TABLE t (id INT)"), new(bytes.Buffer))
+	are.True(errors.Is(err, context.Canceled)) // expected canceled run
+}
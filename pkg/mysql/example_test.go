@@ -0,0 +1,58 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func Example() {
+	t := mysql.NewTable("user", mysql.InnoDB, "latin1")
+	for _, c := range []mysql.Column{
+		{Name: "id", DataType: mysql.Int, NotNull: true},
+		{Name: "email", DataType: mysql.VarChar, DataSize: 64, NotNull: true},
+	} {
+		if err := t.AddColumn(c); err != nil {
+			log.Fatal(err)
+		}
+	}
+	err := t.AddIndex(mysql.Index{
+		Name:    "PRIMARY",
+		Parts:   []mysql.KeyPart{{Column: mysql.Column{Name: "id"}}},
+		Primary: true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	db := mysql.NewDatabase("shop", "latin1")
+	err = db.AddTable(*t)
+	if err != nil {
+		log.Fatal(err)
+	}
+	min, max := ds.Scale(db, 1000)
+	fmt.Println(ds.FormatSize(min, 0), ds.FormatSize(max, 0))
+	// Output: 9 KB 73 KB
+}
+
+func ExampleEstimator_Parse() {
+	e, err := mysql.Estimate(mysql.SetProfiles(mysql.Profiles{"unknown.pet.name": {Average: 10}}))
+	if err != nil {
+		log.Fatal(err)
+	}
+	dbs, err := e.Parse(strings.NewReader("CREATE TABLE pet (name VARCHAR(20) NOT NULL);"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, d := range dbs {
+		min, max := d.Size()
+		fmt.Println(d, min, max, ds.Expect(d).Expected())
+	}
+	// Output: unknown 1 81 11
+}
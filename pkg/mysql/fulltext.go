@@ -0,0 +1,179 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"strconv"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// IndexType represents the type of an index.
+type IndexType string
+
+// List of supported index types.
+const (
+	BTreeIndex    = IndexType("")
+	FullTextIndex = IndexType("fulltext")
+	SpatialIndex  = IndexType("spatial")
+)
+
+// String implements the fmt.Stringer interface.
+func (t IndexType) String() string {
+	return string(t)
+}
+
+// Default values used to estimate the size of a FULLTEXT index.
+const (
+	// DefaultFullTextWords is the average number of distinct words by row.
+	DefaultFullTextWords = 50
+	// DefaultFullTextWordLength is the average number of characters by word.
+	DefaultFullTextWordLength = 6
+)
+
+// InnoDB stores a FULLTEXT index in auxiliary index tables, where each word is stored with
+// the first and last IDs of the documents containing it (2 x 8 bytes), the number of documents (4 bytes),
+// followed by the list of the document IDs and positions (ilist), encoded as variable-length integers.
+// Such a list costs at least 3 bytes by document: the delta of its ID, the position and a terminator.
+// See https://dev.mysql.com/doc/refman/8.0/en/innodb-fulltext-index.html
+const (
+	ftsDocID        = "FTS_DOC_ID"
+	ftsDocIDIndex   = "FTS_DOC_ID_INDEX"
+	ftsWordOverhead = 8 + 8 + 4
+	ftsIListMin     = 1 + 1 + 1
+	ftsIListMax     = 9 + 4 + 1
+)
+
+// MyISAM stores each word of a FULLTEXT index in a B-tree with its weight (float of 4 bytes).
+// See https://dev.mysql.com/doc/refman/8.0/en/key-space.html
+const myISAMFullTextWeight = 4
+
+// An R-tree entry stores the minimum bounding rectangle (MBR) of the geometry:
+// the minimum and maximum values of X and Y coordinates, as doubles.
+const mbrSize = 4 * 8
+
+// fullTextWords returns the number of distinct words and the length of the words, by row, in the index.
+func (i Index) fullTextWords() (words, length uint64) {
+	words, length = i.Words, i.WordLength
+	if words == 0 {
+		words = DefaultFullTextWords
+	}
+	if length == 0 {
+		length = DefaultFullTextWordLength
+	}
+	return
+}
+
+// fullTextSize returns the size by row in the InnoDB auxiliary index tables.
+// At least, each word already exists and only the document is added to its list,
+// at most, each word is new.
+func (i Index) fullTextSize() (min, max uint64) {
+	words, length := i.fullTextWords()
+	size := bytes(length, i.charset())
+	return ds.Mul(words, ftsIListMin), ds.Mul(words, ds.Add(size, ftsWordOverhead+ftsIListMax))
+}
+
+// myISAMFullTextSize returns the size by row of the words in the MyISAM FULLTEXT index.
+func (i Index) myISAMFullTextSize() (min, max uint64) {
+	words, length := i.fullTextWords()
+	return both(ds.Mul(words, ds.Add(bytes(length, i.charset()), 1+myISAMFullTextWeight)))
+}
+
+func (i Index) charset() string {
+	if len(i.Parts) == 0 {
+		return DefaultCharset
+	}
+	return i.Parts[0].Charset
+}
+
+// fullText is a FULLTEXT index definition, not supported by the SQL parser.
+type fullText struct {
+	name    string
+	columns []string
+	words,
+	length uint64
+}
+
+// fullTextDefinition parses a definition, like: FULLTEXT KEY ft (title, body) COMMENT 'ds: words=120 length=5'.
+func fullTextDefinition(def tokens) (fullText, error) {
+	pos, name := keyPartsPosition(def)
+	if pos == len(def) {
+		return fullText{}, ds.WrapErr("fulltext key column", ds.ErrMissing)
+	}
+	if name == defaultKeyName {
+		name = ""
+	}
+	parts, end := def.list(pos)
+	if len(parts) == 0 {
+		return fullText{}, ds.WrapErr("fulltext key column", ds.ErrMissing)
+	}
+	res := fullText{name: name, columns: make([]string, len(parts))}
+	for p, part := range parts {
+		res.columns[p] = part.at(part.next(-1)).text()
+	}
+	if res.name == "" {
+		// Without name, MySQL uses the name of the first column.
+		res.name = res.columns[0]
+	}
+	var err error
+	for pos = def.next(end); pos < len(def); pos = def.next(pos) {
+		if !def.at(pos).is("comment") {
+			continue
+		}
+		if pos = def.next(pos); def.at(pos).is(equal) {
+			pos = def.next(pos)
+		}
+		a := annotations(def.at(pos).text())
+		if v, ok := a[annotationWords]; ok {
+			res.words, err = strconv.ParseUint(v, base10, bits64)
+			if err != nil {
+				return res, ds.WrapErr("fulltext words", ds.ErrInvalid)
+			}
+		}
+		if v, ok := a[annotationLength]; ok {
+			res.length, err = strconv.ParseUint(v, base10, bits64)
+			if err != nil {
+				return res, ds.WrapErr("fulltext word length", ds.ErrInvalid)
+			}
+		}
+	}
+	return res, nil
+}
+
+// Annotations used to describe a FULLTEXT index.
+const (
+	annotationLength = "length"
+	annotationWords  = "words"
+)
+
+// addFullTextKey adds the FULLTEXT index to the table.
+// With InnoDB, if not already declared, the FTS_DOC_ID column and its unique index are added to the table
+// to map each row with its document ID.
+func (t *Table) addFullTextKey(ft fullText) error {
+	parts := make([]KeyPart, len(ft.columns))
+	for p, c := range ft.columns {
+		parts[p].Name = c
+	}
+	err := t.addKey(Index{
+		Name:       ft.name,
+		Parts:      parts,
+		Type:       FullTextIndex,
+		Words:      ft.words,
+		WordLength: ft.length,
+	})
+	if err != nil || t.Engine != InnoDB || t.columnIndex(ftsDocID) != notFound {
+		return err
+	}
+	t.Columns = append(t.Columns, Column{
+		Name:      ftsDocID,
+		DataType:  BigInt,
+		NotNull:   true,
+		Invisible: true,
+	})
+	return t.addKey(Index{
+		Name:  ftsDocIDIndex,
+		Parts: []KeyPart{{Column: Column{Name: ftsDocID}}},
+	})
+}
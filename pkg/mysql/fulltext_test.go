@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestEstimator_Parse_FullText(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in       string
+			opts     []mysql.Configurator
+			typ      mysql.IndexType
+			min, max uint64
+			columns  int
+			err      error
+		}{
+			"Default": {
+				in:  "CREATE TABLE t (c TEXT NOT NULL, FULLTEXT KEY f (c))",
+				typ: mysql.FullTextIndex, min: 150, max: 2900, columns: 2,
+			},
+			"Comment": {
+				in:  "CREATE TABLE t (c TEXT NOT NULL, FULLTEXT KEY f (c) COMMENT 'ds: words=10 length=4')",
+				typ: mysql.FullTextIndex, min: 30, max: 500, columns: 2,
+			},
+			"Charset": {
+				in:  "CREATE TABLE t (c TEXT CHARACTER SET latin1 NOT NULL, FULLTEXT KEY f (c) COMMENT 'ds: words=10 length=4')",
+				typ: mysql.FullTextIndex, min: 30, max: 380, columns: 2,
+			},
+			"Flags": {
+				in:   "CREATE TABLE t (c TEXT NOT NULL, FULLTEXT f (c))",
+				opts: []mysql.Configurator{mysql.SetFullTextWords(5), mysql.SetFullTextWordLength(3)},
+				typ:  mysql.FullTextIndex, min: 15, max: 230, columns: 2,
+			},
+			"MyISAM": {
+				in:  "CREATE TABLE t (c TEXT NOT NULL, FULLTEXT f (c)) ENGINE=MyISAM",
+				typ: mysql.FullTextIndex, min: 150, max: 2900, columns: 1,
+			},
+			"DocID": {
+				in:  "CREATE TABLE t (FTS_DOC_ID BIGINT UNSIGNED NOT NULL, c TEXT NOT NULL, FULLTEXT f (c))",
+				typ: mysql.FullTextIndex, min: 150, max: 2900, columns: 2,
+			},
+			"Spatial": {
+				in:  "CREATE TABLE t (g POINT NOT NULL, SPATIAL KEY s (g))",
+				typ: mysql.SpatialIndex, min: 32, max: 32, columns: 1,
+			},
+			"Unnamed": {
+				in:  "CREATE TABLE t (g POINT NOT NULL, SPATIAL INDEX (g))",
+				typ: mysql.SpatialIndex, min: 32, max: 32, columns: 1,
+			},
+			"Words": {
+				in:  "CREATE TABLE t (c TEXT NOT NULL, FULLTEXT KEY f (c) COMMENT 'ds: words=x')",
+				err: ds.ErrInvalid,
+			},
+			"NoPart":      {in: "CREATE TABLE t (c TEXT NOT NULL, FULLTEXT KEY ())", err: ds.ErrMissing},
+			"NoColumn":    {in: "CREATE TABLE t (c TEXT NOT NULL, FULLTEXT KEY f)", err: ds.ErrMissing},
+			"Unknown":     {in: "CREATE TABLE t (c TEXT NOT NULL, FULLTEXT KEY f (d))", err: ds.ErrInvalid},
+			"Length":      {in: "CREATE TABLE t (c TEXT NOT NULL, FULLTEXT KEY f (c) COMMENT 'ds: length=x')", err: ds.ErrInvalid},
+			"NotString":   {in: "CREATE TABLE t (c INT NOT NULL, FULLTEXT KEY f (c))", err: ds.ErrInvalid},
+			"SpatialNull": {in: "CREATE TABLE t (g POINT, SPATIAL KEY s (g))", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := parse(tt.in, tt.opts...)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			tb := dbs[0].Tables[0]
+			// With InnoDB, the FTS_DOC_ID column is added if not declared.
+			are.Equal(tt.columns, len(tb.Columns)) // mismatch number of columns
+			k := tb.Indexes[0]
+			are.Equal(tt.typ, k.Type) // mismatch index type
+			min, max := k.Size()
+			are.Equal(tt.min, min) // mismatch minimum size
+			are.Equal(tt.max, max) // mismatch maximum size
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Generated represents the storage of a generated column.
+type Generated string
+
+// List of generated column storages.
+const (
+	NotGenerated     = Generated("")
+	StoredGenerated  = Generated("stored")
+	VirtualGenerated = Generated("virtual")
+)
+
+// Virtual returns true if the values of the column are not stored in the rows.
+func (g Generated) Virtual() bool {
+	return g == VirtualGenerated
+}
+
+// String implements the fmt.Stringer interface.
+func (g Generated) String() string {
+	return string(g)
+}
+
+// hiddenPrefix prefixes the name of the hidden virtual column used by a functional key part.
+const hiddenPrefix = "!hidden!"
+
+// hiddenName returns the name of the hidden column of the key part at this position.
+func hiddenName(key string, pos int) string {
+	return fmt.Sprintf("%s%s!%d!0", hiddenPrefix, key, pos)
+}
+
+// functional is a functional key part, indexing the result of an expression.
+type functional struct {
+	name string
+	expr tokens
+}
+
+// generatedColumn extracts the generated column clause and the visibility attribute of the column definition.
+// The column name is kept as is, even if it is one of these keywords, like an unquoted virtual,
+// and the storage keyword is only extracted after the AS (expression) clause.
+// See https://dev.mysql.com/doc/refman/8.0/en/create-table-generated-columns.html
+func generatedColumn(def tokens) (tokens, columnExtension) {
+	var (
+		name = def.next(-1)
+		res  = append(tokens{}, def[:min(name+1, len(def))]...)
+		ext  columnExtension
+	)
+	for pos := name + 1; pos < len(def); pos++ {
+		switch t := def[pos]; {
+		case t.is("generated"):
+			if end, ok := def.words(def.next(pos), "always"); ok {
+				pos = end
+				continue
+			}
+		case t.is("as"):
+			if p := def.next(pos); def.at(p).is("(") {
+				pos = def.closing(p)
+				ext.expression = strings.TrimSpace(def[p+1 : pos].String())
+				ext.generated = VirtualGenerated
+				continue
+			}
+		case t.is("virtual"), t.is("stored"):
+			if ext.expression != "" {
+				ext.generated = Generated(strings.ToLower(t.val))
+				continue
+			}
+		case t.is("invisible"):
+			ext.invisible = true
+			continue
+		case t.is("visible"):
+			continue
+		}
+		res = append(res, def[pos])
+	}
+	return res, ext
+}
+
+// functionalKeyParts replaces the expression of each functional key part by the name of its hidden column.
+// It returns the index definition and the functional key parts.
+func functionalKeyParts(def tokens) (tokens, []functional) {
+	pos, name := keyPartsPosition(def)
+	if pos == len(def) {
+		return def, nil
+	}
+	var (
+		fns        []functional
+		parts, end = def.list(pos)
+	)
+	for p, part := range parts {
+		start := part.next(-1)
+		if !part.at(start).is("(") {
+			continue
+		}
+		f := functional{
+			name: hiddenName(name, p),
+			expr: part[start+1 : part.closing(start)],
+		}
+		parts[p] = append(tokens{{typ: quotedToken, val: "`" + f.name + "`"}}, part[part.closing(start)+1:]...)
+		fns = append(fns, f)
+	}
+	if len(fns) == 0 {
+		return def, nil
+	}
+	return def.replace(pos, end, parts), fns
+}
+
+// intFunction returns true if the function returns an integer.
+// It is used to infer the type of a functional key part.
+func intFunction(name string) bool {
+	switch strings.ToLower(name) {
+	case
+		"ascii", "bit_count", "bit_length", "char_length", "character_length", "crc32",
+		"day", "dayofmonth", "dayofweek", "dayofyear", "hour", "length", "minute", "month",
+		"quarter", "second", "to_days", "unix_timestamp", "week", "weekday", "year":
+		return true
+	default:
+		return false
+	}
+}
+
+// hiddenColumn returns the hidden virtual column storing the values of the functional key part.
+// Its type is the one declared by a cast or inferred from the expression.
+func (f functional) hiddenColumn(cols []Column, charset string) (Column, error) {
+	c, err := f.column(cols, charset)
+	if err != nil {
+		return c, fmt.Errorf("key part %s: %w", f.name, err)
+	}
+	c.Name = f.name
+	c.Expression = strings.TrimSpace(f.expr.String())
+	c.Generated = VirtualGenerated
+	c.Invisible = true
+	return c, nil
+}
+
+func (f functional) column(cols []Column, charset string) (Column, error) {
+	pos := f.expr.next(-1)
+	switch t := f.expr.at(pos); {
+	case t.is("cast"), t.is("convert"):
+		args, _ := f.expr.list(f.expr.next(pos))
+		if len(args) == 0 {
+			return Column{}, ds.ErrInvalid
+		}
+		last := args[len(args)-1]
+		for p := last.next(-1); p < len(last); p = last.next(p) {
+			if last.at(p).is("as") {
+				return castColumn(last[p+1:], charset)
+			}
+		}
+		return castColumn(last, charset)
+	case t.typ == wordToken && f.expr.at(f.expr.next(pos)).is("(") && intFunction(t.val):
+		return Column{DataType: BigInt, NotNull: true}, nil
+	}
+	for p := pos; p < len(f.expr); p = f.expr.next(p) {
+		t := f.expr.at(p)
+		if t.typ != wordToken && t.typ != quotedToken {
+			continue
+		}
+		for _, c := range cols {
+			if strings.EqualFold(c.Name, t.text()) {
+				return c, nil
+			}
+		}
+	}
+	return Column{}, ds.WrapErr("type", ds.ErrMissing)
+}
+
+// castColumn returns a column based on the cast type, like CHAR(64) or UNSIGNED INTEGER.
+// See https://dev.mysql.com/doc/refman/8.0/en/cast-functions.html#function_cast
+func castColumn(typ tokens, charset string) (Column, error) {
+	var (
+		pos = typ.next(-1)
+		res = Column{Charset: charset, NotNull: true}
+	)
+	switch t := typ.at(pos); {
+	case t.is("char"), t.is("nchar"):
+		res.DataType = VarChar
+	case t.is("binary"):
+		res.DataType = VarBinary
+	case t.is("signed"), t.is("unsigned"):
+		res.DataType = BigInt
+	case t.typ == wordToken:
+		res.DataType = ToDataType(t.val)
+	default:
+		return res, ds.WrapErr("cast type", ds.ErrInvalid)
+	}
+	if pos = typ.next(pos); typ.at(pos).is("(") {
+		var err error
+		res.DataSize, err = strconv.ParseUint(typ.at(typ.next(pos)).val, base10, bits64)
+		if err != nil {
+			return res, ds.WrapErr("cast length", ds.ErrInvalid)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestParse_GeneratedColumns(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in         string
+			name       string
+			generated  mysql.Generated
+			invisible  bool
+			expression string
+			size       uint64
+			err        error
+		}{
+			"Virtual":      {in: "b INT AS (a + 1)", name: "b", generated: mysql.VirtualGenerated, expression: "a + 1", size: 4},
+			"Stored":       {in: "b INT GENERATED ALWAYS AS (a + 1) STORED NOT NULL", name: "b", generated: mysql.StoredGenerated, expression: "a + 1", size: 8},
+			"Invisible":    {in: "b INT AS (a * 2) VIRTUAL INVISIBLE", name: "b", generated: mysql.VirtualGenerated, invisible: true, expression: "a * 2", size: 4},
+			"Regular":      {in: "b CHAR(2) NOT NULL INVISIBLE", name: "b", invisible: true, size: 6},
+			"Visible":      {in: "b CHAR(2) NOT NULL VISIBLE", name: "b", size: 6},
+			"Virtual name": {in: "virtual INT NOT NULL", name: "virtual", size: 8},
+			"Stored name":  {in: "stored INT NOT NULL INVISIBLE", name: "stored", invisible: true, size: 8},
+			"Generated name": {
+				in: "generated INT AS (a) STORED NOT NULL", name: "generated", generated: mysql.StoredGenerated, expression: "a", size: 8,
+			},
+			"Expression": {in: "b INT AS (a + 1", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := mysql.Parse(strings.NewReader("CREATE TABLE t (a INT NOT NULL, " + tt.in + ")"))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			tb := dbs[0].Tables[0]
+			are.Equal(2, len(tb.Columns)) // mismatch columns
+			c := tb.Columns[1]
+			are.Equal(tt.name, c.Name)             // mismatch name
+			are.Equal(tt.generated, c.Generated)   // mismatch generated
+			are.Equal(tt.invisible, c.Invisible)   // mismatch invisible
+			are.Equal(tt.expression, c.Expression) // mismatch expression
+			min, max := tb.Size()
+			are.Equal(tt.size, min) // mismatch minimum size
+			are.Equal(tt.size, max) // mismatch maximum size
+		})
+	}
+}
+
+func TestParse_FunctionalKeyParts(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in       string
+			types    []mysql.DataType
+			min, max uint64
+			err      error
+		}{
+			"Inferred":   {in: "KEY k ((lower(e)))", types: []mysql.DataType{mysql.VarChar}, min: 2, max: 402},
+			"Integer":    {in: "KEY k ((year(d)), e)", types: []mysql.DataType{mysql.BigInt, mysql.VarChar}, min: 10, max: 410},
+			"Cast":       {in: "KEY k ((CAST(e AS CHAR(10))))", types: []mysql.DataType{mysql.VarChar}, min: 1, max: 41},
+			"Descending": {in: "KEY k ((lower(e)) DESC, d)", types: []mysql.DataType{mysql.VarChar, mysql.DateTime}, min: 7, max: 407},
+			"CastType":   {in: "KEY k ((CAST(e AS)))", err: ds.ErrInvalid},
+			"CastArgs":   {in: "KEY k ((CAST()))", err: ds.ErrInvalid},
+			"Column":     {in: "KEY k ((lower(x)))", err: ds.ErrMissing},
+			"Empty":      {in: "KEY k (())", err: ds.ErrMissing},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := mysql.Parse(strings.NewReader(
+				"CREATE TABLE t (e VARCHAR(100) NOT NULL, d DATETIME NOT NULL, " + tt.in + ")",
+			))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			tb := dbs[0].Tables[0]
+			are.Equal(1, len(tb.Indexes))                      // mismatch keys
+			are.Equal(len(tt.types), len(tb.Indexes[0].Parts)) // mismatch key parts
+			for p, typ := range tt.types {
+				are.Equal(typ, tb.Indexes[0].Parts[p].DataType) // mismatch key part type
+			}
+			hidden := tb.Columns[2]
+			are.True(hidden.Invisible)                               // expected hidden column
+			are.Equal(mysql.VirtualGenerated, hidden.Generated)      // expected virtual hidden column
+			are.True(strings.HasPrefix(hidden.Name, "!hidden!k!0!")) // mismatch hidden name
+			min, max := tb.Indexes[0].Size()
+			are.Equal(tt.min, min) // mismatch minimum size
+			are.Equal(tt.max, max) // mismatch maximum size
+		})
+	}
+}
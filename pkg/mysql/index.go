@@ -0,0 +1,297 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Index is a table's key.
+// Words and WordLength are only used by a FULLTEXT index to define the average number of distinct words
+// by row and the average number of characters by word. If zero, default values are used.
+type Index struct {
+	Name    string
+	Parts   []KeyPart
+	Primary bool
+	Type    IndexType
+	Words,
+	WordLength uint64
+}
+
+// Size implements the ds.Data interface.
+func (i Index) Size() (min, max uint64) {
+	switch i.Type {
+	case FullTextIndex:
+		return i.fullTextSize()
+	case SpatialIndex:
+		return both(mbrSize)
+	}
+	var n, x uint64
+	for _, c := range i.Parts {
+		n, x = c.Size()
+		min, max = ds.Add(min, n), ds.Add(max, x)
+	}
+	return
+}
+
+// Distribution implements the ds.Distributor interface.
+// The sizes of the key parts of a B-tree index are independent.
+func (i Index) Distribution() ds.Distribution {
+	if i.Type != BTreeIndex {
+		return ds.Uniform(i.Size())
+	}
+	var res ds.Distribution
+	for _, c := range i.Parts {
+		res = res.Add(ds.Expect(c))
+	}
+	return res
+}
+
+// Kind implements the ds.Data interface.
+func (i Index) Kind() string {
+	names := make([]string, len(i.Parts))
+	for p, v := range i.Parts {
+		names[p] = v.String()
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	kind := key
+	if i.Type != BTreeIndex {
+		kind = i.Type.String()
+	}
+	return fmt.Sprintf("%s(%s)", kind, strings.Join(names, ", "))
+}
+
+// String implements the ds.Data interface.
+func (i Index) String() string {
+	return i.Name
+}
+
+// KeyPart is a column of a key.
+// Length is the number of characters of the column used by the key, zero meaning the whole column.
+type KeyPart struct {
+	Column
+	Length uint64
+	Desc   bool
+}
+
+// Size implements the ds.Data interface.
+// With a prefix, only the given length of the column is stored in the key, with its length.
+// As InnoDB, a prefix can not be larger than the column itself.
+func (k KeyPart) Size() (min, max uint64) {
+	min, max = k.Column.Size()
+	if k.Length == 0 {
+		return
+	}
+	if n, x := k.prefixSize(); x < max {
+		return n, x
+	}
+	return
+}
+
+// prefixSize returns the size of the prefix of the column, with its length if the column is variable-length.
+func (k KeyPart) prefixSize() (min, max uint64) {
+	size := k.Length
+	if !k.DataType.IsBinary() {
+		size = bytes(k.Length, k.Charset)
+	}
+	if !k.DataType.IsVar() {
+		return both(size)
+	}
+	if size > math.MaxUint8 {
+		return 2, ds.Add(size, 2)
+	}
+	return 1, ds.Add(size, 1)
+}
+
+// Distribution implements the ds.Distributor interface.
+func (k KeyPart) Distribution() ds.Distribution {
+	if k.Length == 0 {
+		return k.Column.Distribution()
+	}
+	min, max := k.Size()
+	return k.Profile.distribution(min, max, k.DataType.IsVar(), false)
+}
+
+// String implements the ds.Data interface.
+func (k KeyPart) String() string {
+	var s string
+	if k.hidden() {
+		s = "(" + k.Expression + ")"
+	} else {
+		s = k.Name
+	}
+	if k.Length > 0 {
+		s += "(" + strconv.FormatUint(k.Length, base10) + ")"
+	}
+	if k.Desc {
+		s += " " + desc
+	}
+	return s
+}
+
+const (
+	desc = "desc"
+	key  = "key"
+)
+
+// valid checks the key part against the column and the type of index.
+func (k KeyPart) valid(typ IndexType) error {
+	switch typ {
+	case FullTextIndex:
+		if !k.DataType.IsString() || k.DataType.IsBinary() {
+			return ds.WrapErr("fulltext key part of "+k.Name, ds.ErrInvalid)
+		}
+		return nil
+	case SpatialIndex:
+		// Only NOT NULL spatial columns can be indexed with an R-tree, without prefix.
+		if !k.DataType.IsSpatial() || !k.NotNull || k.Length > 0 {
+			return ds.WrapErr("spatial key part of "+k.Name, ds.ErrInvalid)
+		}
+		return nil
+	}
+	if k.Length == 0 && k.DataType.IsBlob() {
+		// BLOB and TEXT columns can only be indexed with a prefix.
+		return ds.WrapErr("key part length of "+k.Name, ds.ErrMissing)
+	}
+	if k.Length > 0 && !k.DataType.IsString() && !k.DataType.IsSpatial() {
+		return ds.WrapErr("key part length of "+k.Name, ds.ErrInvalid)
+	}
+	return nil
+}
+
+// keyPartsOrder extracts the order (ASC or DESC) of each key part of the index definition,
+// not supported by the SQL parser.
+func keyPartsOrder(def tokens) (tokens, []bool) {
+	pos, _ := keyPartsPosition(def)
+	if pos == len(def) {
+		return def, nil
+	}
+	parts, end := def.list(pos)
+	res := make([]bool, len(parts))
+	for p, part := range parts {
+		var last int
+		for i := part.next(-1); i < len(part); i = part.next(i) {
+			last = i
+		}
+		switch t := part.at(last); {
+		case t.is("asc"):
+		case t.is(desc):
+			res[p] = true
+		default:
+			continue
+		}
+		parts[p] = part[:last]
+	}
+	return def.replace(pos, end, parts), res
+}
+
+// Names given by MySQL to the primary key and to a functional index without name.
+const (
+	defaultKeyName = "functional_index"
+	primaryKeyName = "PRIMARY"
+)
+
+// keyPartsPosition returns the position of the parenthesis opening the key parts of the index definition
+// and the name of the index.
+func keyPartsPosition(def tokens) (pos int, name string) {
+	for pos = def.next(-1); pos < len(def); pos = def.next(pos) {
+		switch t := def.at(pos); {
+		case t.is("("):
+			if name == "" {
+				name = defaultKeyName
+			}
+			return pos, name
+		case t.is("primary"):
+			name = primaryKeyName
+		case t.typ == quotedToken, t.typ == wordToken && !keyword(t):
+			name = t.text()
+		}
+	}
+	return len(def), name
+}
+
+// columnKey extracts the key attribute of the column definition, PRIMARY KEY, UNIQUE [KEY] or KEY,
+// the last one being a synonym of PRIMARY KEY in a column definition.
+// It returns the definition without it and if the column is the primary or a unique key.
+func columnKey(def tokens) (res tokens, primary, unique bool) {
+	start := def.next(def.next(-1))
+	res = append(tokens{}, def[:start]...)
+	for pos := start; pos < len(def); pos++ {
+		switch t := def[pos]; {
+		case t.is("primary"), t.is(key):
+			primary = true
+			if end, ok := def.words(def.next(pos), key); ok && t.is("primary") {
+				pos = end
+			}
+			continue
+		case t.is("unique"):
+			unique = true
+			if end, ok := def.words(def.next(pos), key); ok {
+				pos = end
+			}
+			continue
+		}
+		res = append(res, def[pos])
+	}
+	return
+}
+
+func keyword(t token) bool {
+	for _, s := range []string{
+		"constraint", "primary", "unique", "fulltext", "spatial", "key", "index", "using", "btree", "hash",
+	} {
+		if t.is(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexTypeOption removes the index type declared before the key parts, like USING BTREE,
+// not supported by the SQL parser. It has no impact on the sizes.
+func indexTypeOption(def tokens) tokens {
+	for p := def.next(-1); p < len(def) && !def.at(p).is("("); p = def.next(p) {
+		if !def.at(p).is("using") {
+			continue
+		}
+		res := append(tokens{}, def[:p]...)
+		return append(res, def[min(def.next(p)+1, len(def)):]...)
+	}
+	return def
+}
+
+// namedIndex returns the index definition with a name, the one of its first column if none, as MySQL does.
+// The SQL parser does not support the indexes without name.
+func namedIndex(def tokens) tokens {
+	var prev token
+	for p := def.next(-1); p < len(def); p = def.next(p) {
+		t := def.at(p)
+		switch {
+		case t.is("primary"), t.is("foreign"), t.is("check"):
+			return def
+		case !t.is("("):
+			prev = t
+			continue
+		case !prev.is("key") && !prev.is("index") && !prev.is("unique"):
+			return def
+		}
+		parts, _ := def.list(p)
+		if len(parts) == 0 {
+			return def
+		}
+		_, name, _ := parts[0].name(parts[0].next(-1))
+		res := append(tokens{}, def[:p]...)
+		res = append(res, token{typ: quotedToken, val: "`" + strings.ReplaceAll(name, "`", "``") + "`"})
+		return append(res, def[p:]...)
+	}
+	return def
+}
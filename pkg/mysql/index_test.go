@@ -0,0 +1,55 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestParse_PrefixKeyParts(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in       string
+			min, max uint64
+			desc     bool
+			err      error
+		}{
+			"Whole":      {in: "c CHAR(10) NOT NULL, KEY (c)", min: 10, max: 10},
+			"Char":       {in: "c CHAR(10) CHARACTER SET latin1 NOT NULL, KEY (c(4))", min: 4, max: 4},
+			"Charset":    {in: "c VARCHAR(100) NOT NULL, KEY (c(4))", min: 1, max: 17},
+			"Binary":     {in: "c VARBINARY(100) NOT NULL, KEY (c(4))", min: 1, max: 5},
+			"Text":       {in: "c TEXT NOT NULL, KEY (c(100))", min: 2, max: 402},
+			"Descending": {in: "c VARCHAR(100) NOT NULL, KEY (c(4) DESC)", min: 1, max: 17, desc: true},
+			"Char clamp": {in: "c CHAR(10) NOT NULL, KEY (c(4))", min: 10, max: 10},
+			"Var clamp":  {in: "c VARCHAR(2) NOT NULL, KEY (c(4))", min: 1, max: 9},
+			"Blob clamp": {in: "c TINYBLOB NOT NULL, KEY (c(300))", min: 1, max: 255},
+			"Blob":       {in: "c BLOB NOT NULL, KEY (c)", err: ds.ErrMissing},
+			"Numeric":    {in: "c INT NOT NULL, KEY (c(4))", err: ds.ErrInvalid},
+			"Column":     {in: "c CHAR(10) NOT NULL, KEY (d(4))", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := mysql.Parse(strings.NewReader("CREATE TABLE t (" + tt.in + ")"))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			k := dbs[0].Tables[0].Indexes[0].Parts[0]
+			min, max := k.Size()
+			are.Equal(tt.min, min)     // mismatch minimum size
+			are.Equal(tt.max, max)     // mismatch maximum size
+			are.Equal(tt.desc, k.Desc) // mismatch order
+		})
+	}
+}
@@ -0,0 +1,494 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tokenType is the type of a lexical token.
+type tokenType int
+
+// List of lexical token types.
+const (
+	spaceToken     tokenType = iota
+	wordToken                // keyword, identifier or number.
+	quotedToken              // `identifier`
+	stringToken              // 'string' or "string"
+	commentToken             // -- comment, # comment or /* comment */
+	symbolToken              // (, ), comma, etc.
+	delimiterToken           // semicolon or the delimiter set by the DELIMITER command.
+)
+
+// defaultDelimiter is the delimiter of the statements, until changed by the DELIMITER command.
+const defaultDelimiter = ";"
+
+// token is a lexical token of a SQL statement.
+type token struct {
+	typ tokenType
+	val string
+	// open is true if the quote of the token is never closed, like in a truncated statement.
+	open bool
+}
+
+// is returns true if the token is the given word or symbol, case-insensitively.
+func (t token) is(s string) bool {
+	return (t.typ == wordToken || t.typ == symbolToken) && strings.EqualFold(t.val, s)
+}
+
+// text returns the value of the token without its quotes.
+func (t token) text() string {
+	switch t.typ {
+	case quotedToken, stringToken:
+		q, s := t.val[:1], t.val[1:]
+		if !t.open {
+			s = s[:len(s)-1]
+		}
+		return strings.ReplaceAll(s, q+q, q)
+	default:
+		return t.val
+	}
+}
+
+// tokens is a list of lexical tokens.
+type tokens []token
+
+// lex splits the SQL statements into lexical tokens.
+// As with the MySQL client, the DELIMITER command changes the delimiter of the statements,
+// to declare those with semicolons in their body, like the triggers.
+// The body of the versioned comments, like /*!50100 PARTITION BY HASH (id) */, is lexed as regular tokens,
+// only their markers are comments.
+func lex(s string) tokens {
+	var (
+		res       tokens
+		r         = []rune(s)
+		delim     = []rune(defaultDelimiter)
+		first     = true
+		versioned bool
+	)
+	for i := 0; i < len(r); {
+		j := i + 1
+		t := symbolToken
+		open := false
+		switch c := r[i]; {
+		case hasPrefix(r[i:], delim):
+			t = delimiterToken
+			j = i + len(delim)
+		case unicode.IsSpace(c):
+			t = spaceToken
+			for j < len(r) && unicode.IsSpace(r[j]) {
+				j++
+			}
+		case c == '#', c == '-' && j < len(r) && r[j] == '-':
+			t = commentToken
+			for j < len(r) && r[j] != '\n' {
+				j++
+			}
+		case hasPrefix(r[i:], []rune("/*!")), hasPrefix(r[i:], []rune("/*M!")):
+			// Only the marker and its optional version number, like /*!50100, are a comment.
+			t = commentToken
+			versioned = true
+			for j = i + 2; r[j] != '!'; j++ {
+			}
+			for j++; j < len(r) && unicode.IsDigit(r[j]); j++ {
+			}
+		case versioned && c == '*' && j < len(r) && r[j] == '/':
+			t = commentToken
+			versioned = false
+			j++
+		case c == '/' && j < len(r) && r[j] == '*':
+			t = commentToken
+			for j++; j < len(r) && !(r[j-1] == '*' && r[j] == '/'); j++ {
+			}
+			j = min(j+1, len(r))
+		case c == '`', c == '\'', c == '"':
+			t = stringToken
+			if c == '`' {
+				t = quotedToken
+			}
+			for ; j < len(r); j++ {
+				if r[j] == '\\' && c != '`' {
+					j++
+					continue
+				}
+				if r[j] == c {
+					if j+1 < len(r) && r[j+1] == c {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			open = j >= len(r)
+			j = min(j+1, len(r))
+		case isWord(c):
+			t = wordToken
+			for j < len(r) && isWord(r[j]) && !hasPrefix(r[j:], delim) {
+				j++
+			}
+			if first && strings.EqualFold(string(r[i:j]), delimiterCommand) {
+				// The command is not a statement: it is ignored as a comment until the end of its line.
+				t = commentToken
+				for j < len(r) && r[j] != '\n' {
+					j++
+				}
+				if f := strings.Fields(string(r[i+len(delimiterCommand) : j])); len(f) > 0 {
+					delim = []rune(f[0])
+				}
+			}
+		}
+		switch t {
+		case delimiterToken:
+			first = true
+		case spaceToken, commentToken:
+		default:
+			first = false
+		}
+		res = append(res, token{typ: t, val: string(r[i:j]), open: open})
+		i = j
+	}
+	return res
+}
+
+// delimiterCommand is the command of the MySQL client changing the delimiter of the statements.
+const delimiterCommand = "delimiter"
+
+// hasPrefix returns true if the runes begin with the prefix.
+func hasPrefix(r, prefix []rune) bool {
+	if len(prefix) == 0 || len(r) < len(prefix) {
+		return false
+	}
+	for i, c := range prefix {
+		if r[i] != c {
+			return false
+		}
+	}
+	return true
+}
+
+func isWord(r rune) bool {
+	return r == '_' || r == '$' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// split splits the tokens into statements, using the delimiters as separator.
+func (ts tokens) split() []tokens {
+	var (
+		res []tokens
+		cur tokens
+	)
+	for _, t := range ts {
+		if t.typ == delimiterToken {
+			res = append(res, cur)
+			cur = nil
+			continue
+		}
+		cur = append(cur, t)
+	}
+	return append(res, cur)
+}
+
+// String returns the SQL statement.
+func (ts tokens) String() string {
+	var b strings.Builder
+	for _, t := range ts {
+		b.WriteString(t.val)
+	}
+	return b.String()
+}
+
+// sql returns the SQL statement without its comments, each of them replaced by a space.
+// The markers of the versioned comments being comments, their body is kept as is.
+func (ts tokens) sql() string {
+	var b strings.Builder
+	for _, t := range ts {
+		if t.typ == commentToken {
+			b.WriteString(" ")
+			continue
+		}
+		b.WriteString(t.val)
+	}
+	return b.String()
+}
+
+// unterminated returns true if the statement ends with a quote never closed.
+// Such a quoted token runs until the end of the statements, so it can only be the last one.
+func (ts tokens) unterminated() bool {
+	return len(ts) > 0 && ts[len(ts)-1].open
+}
+
+// unclosed returns true if a parenthesis of the statement is never closed.
+func (ts tokens) unclosed() bool {
+	var depth int
+	for _, t := range ts {
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		}
+	}
+	return depth > 0
+}
+
+// empty returns true if the statement only contains spaces or comments.
+func (ts tokens) empty() bool {
+	return ts.next(-1) == len(ts)
+}
+
+// next returns the position of the next meaningful token after the given position,
+// ignoring spaces and comments. It returns the length of the list if there is no other one.
+func (ts tokens) next(pos int) int {
+	for pos++; pos < len(ts); pos++ {
+		if ts[pos].typ != spaceToken && ts[pos].typ != commentToken {
+			return pos
+		}
+	}
+	return len(ts)
+}
+
+// at returns the meaningful token at this position, or an empty one if it is out of range.
+func (ts tokens) at(pos int) token {
+	if pos < 0 || pos >= len(ts) {
+		return token{}
+	}
+	return ts[pos]
+}
+
+// closing returns the position of the parenthesis closing the one opened at the given position.
+// It returns the length of the list if the parenthesis is not closed.
+func (ts tokens) closing(pos int) int {
+	var depth int
+	for ; pos < len(ts); pos++ {
+		switch {
+		case ts[pos].is("("):
+			depth++
+		case ts[pos].is(")"):
+			depth--
+			if depth == 0 {
+				return pos
+			}
+		}
+	}
+	return len(ts)
+}
+
+// words returns true if the meaningful tokens from this position match the given words.
+// It also returns the position of the last of them.
+func (ts tokens) words(pos int, words ...string) (int, bool) {
+	pos = ts.next(pos - 1)
+	for p, w := range words {
+		if !ts.at(pos).is(w) {
+			return pos, false
+		}
+		if p < len(words)-1 {
+			pos = ts.next(pos)
+		}
+	}
+	return pos, true
+}
+
+// list splits the tokens between the parentheses opened at the given position
+// by using the top level commas as separator. It also returns the position of the closing parenthesis.
+func (ts tokens) list(pos int) ([]tokens, int) {
+	end := ts.closing(pos)
+	return ts.separate(pos+1, end), end
+}
+
+// separate splits the tokens between these positions by using the top level commas as separator.
+func (ts tokens) separate(start, end int) []tokens {
+	var (
+		res   []tokens
+		depth int
+	)
+	for i := start; i < end; i++ {
+		switch {
+		case ts[i].is("("):
+			depth++
+		case ts[i].is(")"):
+			depth--
+		case ts[i].is(",") && depth == 0:
+			res = append(res, ts[start:i])
+			start = i + 1
+		}
+	}
+	if start < end {
+		res = append(res, ts[start:end])
+	}
+	return res
+}
+
+// name returns the identifier at this position, with its qualifier if any, like shop.user or `shop`.`user`.
+// It also returns the position of its last token.
+func (ts tokens) name(pos int) (qualifier, name string, end int) {
+	parts := []string{""}
+	for end = pos; end < len(ts); end++ {
+		t := ts[end]
+		if t.typ == quotedToken || t.typ == stringToken {
+			parts[len(parts)-1] += t.text()
+			continue
+		}
+		if t.typ != wordToken {
+			break
+		}
+		a := strings.Split(t.val, ".")
+		parts[len(parts)-1] += a[0]
+		parts = append(parts, a[1:]...)
+	}
+	if len(parts) > 1 {
+		qualifier = parts[len(parts)-2]
+	}
+	return qualifier, parts[len(parts)-1], end - 1
+}
+
+// replace replaces the tokens between the parentheses at the given positions
+// by the list of tokens, separated by commas.
+func (ts tokens) replace(start, end int, list []tokens) tokens {
+	res := make(tokens, 0, len(ts))
+	res = append(res, ts[:start+1]...)
+	for p, v := range list {
+		if p > 0 {
+			res = append(res, token{typ: symbolToken, val: ","})
+		}
+		res = append(res, v...)
+	}
+	return append(res, ts[end:]...)
+}
+
+// definitions returns the definitions of the columns and indexes of the CREATE TABLE statement,
+// with the positions of the parentheses around them.
+func (ts tokens) definitions() (start int, defs []tokens, end int) {
+	for start = 0; start < len(ts); start++ {
+		if ts[start].is("(") {
+			defs, end = ts.list(start)
+			return
+		}
+	}
+	return len(ts), nil, len(ts)
+}
+
+// indexDefinition returns true if the definition describes an index or a constraint, not a column.
+func (ts tokens) indexDefinition() bool {
+	t := ts.at(ts.next(-1))
+	for _, s := range []string{
+		"check", "constraint", "foreign", "fulltext", "index", "key", "primary", "spatial", "unique",
+	} {
+		if t.is(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// fullTextDefinition returns true if the definition describes a FULLTEXT index.
+func (ts tokens) fullTextDefinition() bool {
+	return ts.at(ts.next(-1)).is("fulltext")
+}
+
+// createTable returns true if the statement creates a table.
+func (ts tokens) createTable() bool {
+	pos, ok := ts.words(0, "create")
+	if !ok {
+		return false
+	}
+	pos = ts.next(pos)
+	if ts.at(pos).is("temporary") {
+		pos = ts.next(pos)
+	}
+	return ts.at(pos).is("table")
+}
+
+// quoteName returns the definition with its name quoted, to be parsed even if it is a keyword of the SQL parser,
+// like an unquoted column named virtual.
+func (ts tokens) quoteName() tokens {
+	pos := ts.next(-1)
+	if ts.at(pos).typ != wordToken {
+		return ts
+	}
+	res := append(tokens{}, ts...)
+	res[pos] = token{typ: quotedToken, val: "`" + ts[pos].val + "`"}
+	return res
+}
+
+// ifNotExists returns true if the CREATE statement is declared with IF NOT EXISTS.
+func (ts tokens) ifNotExists() bool {
+	for p := ts.next(-1); p < len(ts) && !ts.at(p).is("("); p = ts.next(p) {
+		if _, ok := ts.words(p, "if", "not", "exists"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// charset returns the charset declared by the statement, like CHARACTER SET latin1 or CHARSET=latin1, if any.
+func (ts tokens) charset() string {
+	for pos := ts.next(-1); pos < len(ts); pos = ts.next(pos) {
+		end, ok := ts.words(pos, "character", "set")
+		if !ok && !ts.at(pos).is(characterSet) {
+			continue
+		}
+		return ts.at(ts.optional(ts.next(end), equal)).text()
+	}
+	return ""
+}
+
+// ignored returns true if the statement has no effect on the data sizes, like the LOCK TABLES, SET, INSERT
+// or GRANT statements of a dump, or the creation of a trigger or a view.
+// Such statements are skipped, most of them not being supported by the SQL parser.
+func (ts tokens) ignored() bool {
+	pos := ts.next(-1)
+	switch strings.ToLower(ts.at(pos).val) {
+	case "lock", "unlock", "grant", "revoke", "flush", "set",
+		"insert", "replace", "update", "delete", "select", "call", "start", "commit", "rollback":
+		return true
+	case "alter":
+		if _, ok := ts.words(ts.next(pos), "database"); ok {
+			return true
+		}
+		if _, ok := ts.words(ts.next(pos), "schema"); ok {
+			return true
+		}
+	case "create", "drop":
+	default:
+		return false
+	}
+	for pos = ts.next(pos); pos < len(ts); pos = ts.next(pos) {
+		switch strings.ToLower(ts.at(pos).val) {
+		case "table", "database", "schema", "index":
+			return false
+		case "event", "function", "procedure", "role", "server", "trigger", "user", "view":
+			return true
+		}
+	}
+	return false
+}
+
+// annotation is the prefix used in comments to describe data to the estimator.
+const annotation = "ds:"
+
+// annotations returns the properties declared in the comment after the annotation prefix,
+// like "ds: weight=3 avg=24".
+func annotations(comment string) map[string]string {
+	comment = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(comment, "/*"), "*/"))
+	comment = strings.TrimSpace(strings.TrimLeft(comment, "-#"))
+	if !strings.HasPrefix(strings.ToLower(comment), annotation) {
+		return nil
+	}
+	res := make(map[string]string)
+	for _, s := range strings.Fields(comment[len(annotation):]) {
+		kv := strings.SplitN(s, equal, pair)
+		if len(kv) == pair {
+			res[strings.ToLower(kv[0])] = kv[1]
+		}
+	}
+	return res
+}
@@ -0,0 +1,402 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/xwb1989/sqlparser"
+)
+
+// defaultDatabaseName is the name used by default for a database.
+const defaultDatabaseName = "unknown"
+
+// Parse parses the given SQL statements as MySQL queries.
+// It tries to convert it as a Storage. A statement that can not be parsed fails with its syntax error.
+func Parse(r io.Reader) (Storage, error) {
+	return parse(context.Background(), r, defaults{}, nil)
+}
+
+// defaults are the settings used when the SQL statements do not declare them.
+// If empty, DefaultCharset and InnoDB are used.
+type defaults struct {
+	charset string
+	engine  Engine
+}
+
+// parse parses the SQL statements. If not nil, skip is called with the syntax error of each statement
+// that can not be parsed: the statement is skipped unless an error is returned.
+func parse(ctx context.Context, r io.Reader, def defaults, skip func(error) error) (Storage, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	s := newSchema(def)
+	for _, ts := range lex(string(b)).split() {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		_, err = s.exec(ts)
+		if skip != nil && errors.As(err, &statementError{}) {
+			err = skip(err)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.dbs, nil
+}
+
+// schema is the state of the statements already parsed: the databases and the current one.
+type schema struct {
+	dbs Storage
+	cur string
+	def defaults
+}
+
+func newSchema(def defaults) *schema {
+	return &schema{dbs: Storage{}, cur: defaultDatabaseName, def: def}
+}
+
+// change names the data changed by a statement. The database is empty if none.
+type change struct {
+	database,
+	table string
+	// fromDatabase and fromTable name the table before being renamed, if so.
+	fromDatabase,
+	fromTable string
+}
+
+// exec applies the SQL statement on the schema, and returns the changed data.
+func (s *schema) exec(ts tokens) (res change, err error) {
+	if ts.empty() {
+		return
+	}
+	if ts.unterminated() {
+		return res, syntaxError(ts, errUnterminated)
+	}
+	if ts.unclosed() {
+		return res, syntaxError(ts, errUnclosed)
+	}
+	ts, ext, err := extend(ts)
+	if err != nil {
+		return
+	}
+	if a, ok, err := alterStatement(ts); ok || err != nil {
+		if err != nil {
+			return res, err
+		}
+		db := s.database(a.database)
+		res.database, res.table = db, a.table
+		if a.toTable != "" {
+			a.toDatabase = s.database(a.toDatabase)
+			res.fromDatabase, res.fromTable = db, a.table
+			res.database, res.table = a.toDatabase, a.toTable
+		}
+		return res, s.dbs.alterTable(db, a)
+	}
+	if pos, ok := ts.words(0, "drop", "table"); ok {
+		return s.dropTables(ts, pos)
+	}
+	if pos, ok := ts.words(0, "rename", "table"); ok {
+		return s.renameTables(ts, pos)
+	}
+	if ts.ignored() {
+		return
+	}
+	stmt, err := sqlparser.ParseNext(sqlparser.NewStringTokenizer(ts.sql()))
+	if err != nil {
+		return res, syntaxError(ts, err)
+	}
+	switch stmt := stmt.(type) {
+	case *sqlparser.DBDDL:
+		res.database = stmt.DBName
+		switch stmt.Action {
+		case sqlparser.CreateStr:
+			// The SQL parser ignores the options of the database.
+			s.dbs, s.cur = s.dbs.addDatabase(stmt.DBName, Charset(stmt.Charset, ts.charset(), s.def.charset))
+		case sqlparser.DropStr:
+			s.dbs = s.dbs.dropDatabase(stmt.DBName)
+		}
+	case *sqlparser.Use:
+		s.cur = stmt.DBName.String()
+	case *sqlparser.DDL:
+		// By default, if no database are specified, we use a default one to wrap any tables.
+		res.database = s.database(stmt.NewName.Qualifier.String())
+		if stmt.Action == sqlparser.CreateStr {
+			res.table = stmt.NewName.Name.String()
+			err = s.dbs.createTable(res.database, stmt, ext, s.def.engine, ts.ifNotExists())
+		}
+	}
+	return res, err
+}
+
+// Syntax errors of truncated statements.
+const (
+	// errUnclosed is returned when a parenthesis of the statement is never closed.
+	errUnclosed = ds.Error("unclosed parenthesis")
+	// errUnterminated is returned when a quote of the statement is never closed.
+	errUnterminated = ds.Error("unterminated quoted string")
+)
+
+// maxStatementLength is the maximum number of characters of a statement displayed in an error.
+const maxStatementLength = 48
+
+// syntaxError returns the error of the SQL parser with the statement, shortened on a single line.
+func syntaxError(ts tokens, err error) error {
+	s := strings.Join(strings.Fields(ts.sql()), " ")
+	if r := []rune(s); len(r) > maxStatementLength {
+		s = string(r[:maxStatementLength]) + "..."
+	}
+	return statementError{stmt: s, err: err}
+}
+
+// statementError is the syntax error of a statement.
+type statementError struct {
+	stmt string
+	err  error
+}
+
+// Error implements the error interface.
+func (e statementError) Error() string {
+	return fmt.Sprintf("statement %q: %s: %s", e.stmt, e.err, ds.ErrInvalid)
+}
+
+// Unwrap returns ds.ErrInvalid, as for any invalid data.
+func (e statementError) Unwrap() error {
+	return ds.ErrInvalid
+}
+
+// database returns the name of the qualifier database, or of the current one without qualifier.
+// A database used without being created, like the default one, is created with the default charset.
+func (s *schema) database(qualifier string) string {
+	name := qualifier
+	if name == "" {
+		name = s.cur
+	}
+	if _, err := s.dbs.get(name); err != nil {
+		s.dbs, _ = s.dbs.addDatabase(name, Charset(s.def.charset))
+	}
+	return name
+}
+
+// dropTables drops the tables listed from this position of the DROP TABLE statement.
+// The last one is returned as changed.
+func (s *schema) dropTables(ts tokens, pos int) (res change, err error) {
+	pos = ts.next(pos)
+	end, ifExists := ts.words(pos, "if", "exists")
+	if ifExists {
+		pos = ts.next(end)
+	}
+	for _, def := range ts.separate(pos, len(ts)) {
+		db, name, _ := def.name(def.next(-1))
+		res.database, res.table = s.database(db), name
+		err = s.dbs.dropTable(res.database, name, ifExists)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// renameTables renames the tables listed from this position of the RENAME TABLE statement, like a TO b, c TO d.
+// The last one is returned as changed.
+func (s *schema) renameTables(ts tokens, pos int) (res change, err error) {
+	for _, def := range ts.separate(ts.next(pos), len(ts)) {
+		db, name, end := def.name(def.next(-1))
+		end, ok := def.words(def.next(end), "to")
+		if !ok {
+			return res, ds.WrapErr("rename table", ds.ErrInvalid)
+		}
+		res.fromDatabase, res.fromTable = s.database(db), name
+		db, name, _ = def.name(def.next(end))
+		res.database, res.table = s.database(db), name
+		err = s.dbs.renameTable(res.fromDatabase, res.fromTable, res.database, res.table)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// extension contains the table properties not supported by the SQL parser.
+type extension struct {
+	columns      map[string]columnExtension
+	fullText     []fullText
+	functional   []functional
+	keys         []keyExtension
+	partitioning Partitioning
+}
+
+// keyExtension contains the index properties not supported by the SQL parser.
+type keyExtension struct {
+	desc []bool
+}
+
+// desc returns true if the key part at this position of the index is sorted in descending order.
+func (e extension) desc(index, part int) bool {
+	if index >= len(e.keys) || part >= len(e.keys[index].desc) {
+		return false
+	}
+	return e.keys[index].desc[part]
+}
+
+// columnExtension contains the column properties not supported by the SQL parser.
+type columnExtension struct {
+	expression string
+	generated  Generated
+	invisible  bool
+	srid       uint32
+	primary,
+	unique bool
+	profile Profile
+	// dataType is the data type declared, if replaced because of the invalid issue.
+	dataType DataType
+	invalid  error
+}
+
+// extend extracts from the statement the properties not supported by the SQL parser.
+// It returns the statement without them.
+func extend(stmt tokens) (tokens, extension, error) {
+	var (
+		ext extension
+		err error
+	)
+	if !stmt.createTable() {
+		return stmt, ext, nil
+	}
+	stmt, ext.partitioning, err = partitioning(stmt)
+	if err != nil {
+		return nil, ext, err
+	}
+	start, defs, end := stmt.definitions()
+	if start < end && end == len(stmt) {
+		return nil, ext, syntaxError(stmt, errUnclosed)
+	}
+	if len(defs) == 0 {
+		return stmt, ext, nil
+	}
+	ext.columns = make(map[string]columnExtension)
+	res := make([]tokens, 0, len(defs))
+	for i, def := range defs {
+		switch {
+		case def.fullTextDefinition():
+			err = ext.addFullText(def)
+			if err != nil {
+				return nil, ext, err
+			}
+		case def.indexDefinition():
+			res = append(res, ext.index(def))
+		default:
+			var next tokens
+			if i+1 < len(defs) {
+				next = defs[i+1]
+			} else {
+				next = stmt[end+1:]
+			}
+			def, err = ext.column(def, next)
+			if err != nil {
+				return nil, ext, err
+			}
+			res = append(res, def)
+		}
+	}
+	return stmt.replace(start, end, res), ext, nil
+}
+
+// column extracts the unsupported properties of the column definition.
+// The next definition is used to find the line comment following this one.
+func (e *extension) column(def, next tokens) (tokens, error) {
+	p, err := columnProfile(def, next)
+	if err != nil {
+		return nil, err
+	}
+	def, c := generatedColumn(def.quoteName())
+	c.profile = p
+	def, serial, err := dataTypeAlias(def)
+	if err != nil {
+		return nil, err
+	}
+	def, c.dataType, c.invalid = invalidDataType(def)
+	def, srid, err := spatialAttributes(def)
+	if err != nil {
+		return nil, err
+	}
+	def, c.primary, c.unique = columnKey(def)
+	c.srid = srid
+	c.unique = c.unique || serial
+	e.columns[def.at(def.next(-1)).text()] = c
+	return def, nil
+}
+
+// index extracts the unsupported properties of the index definition.
+func (e *extension) index(def tokens) tokens {
+	var k keyExtension
+	def = namedIndex(indexTypeOption(def))
+	def, k.desc = keyPartsOrder(def)
+	def, fns := functionalKeyParts(def)
+	e.functional = append(e.functional, fns...)
+	e.keys = append(e.keys, k)
+	return def
+}
+
+// addFullText extracts the FULLTEXT index definition, not supported by the SQL parser.
+func (e *extension) addFullText(def tokens) error {
+	ft, err := fullTextDefinition(def)
+	if err != nil {
+		return err
+	}
+	e.fullText = append(e.fullText, ft)
+	return nil
+}
+
+// apply applies the unsupported properties on the table, before adding its keys.
+func (e extension) apply(t *Table, charset string) error {
+	t.addDiagnostics(e.setColumns(t.Columns))
+	err := e.addHiddenColumns(t, charset)
+	if err != nil {
+		return err
+	}
+	t.Partitioning = e.partitioning
+	return nil
+}
+
+// setColumns applies the unsupported properties on the columns.
+// It returns the diagnostics of the columns with an invalid data type, excluded from the estimation.
+func (e extension) setColumns(cols []Column) []Diagnostic {
+	var res []Diagnostic
+	for p, c := range cols {
+		v := e.columns[c.Name]
+		cols[p].Expression = v.expression
+		cols[p].Generated = v.generated
+		cols[p].Invisible = v.invisible
+		cols[p].SRID = v.srid
+		cols[p].Profile = v.profile
+		if v.invalid == nil {
+			continue
+		}
+		cols[p].DataType, cols[p].DataSize, cols[p].Excluded = v.dataType, 0, true
+		res = append(res, Diagnostic{Column: c.Name, Err: v.invalid})
+	}
+	return res
+}
+
+// addHiddenColumns adds to the table the hidden columns of the functional key parts.
+func (e extension) addHiddenColumns(t *Table, charset string) error {
+	for _, f := range e.functional {
+		c, err := f.hiddenColumn(t.Columns, charset)
+		if err != nil {
+			return err
+		}
+		t.Columns = append(t.Columns, c)
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestParse_Statements(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in      string
+			tables  []string
+			columns []string
+			err     error
+		}{
+			"Quoted": {
+				in:     "CREATE TABLE `a``b` (`c;d` INT NOT NULL);",
+				tables: []string{"a`b"}, columns: []string{"c;d"},
+			},
+			"String": {
+				in:     "CREATE TABLE t (c INT NOT NULL COMMENT 'a;b'' -- c', d VARCHAR(2) DEFAULT \"e;\");",
+				tables: []string{"t"}, columns: []string{"c", "d"},
+			},
+			"Comments": {
+				in:     "-- a;\n# b;\n/* c; */ CREATE TABLE t (c INT NOT NULL) /* d; */",
+				tables: []string{"t"}, columns: []string{"c"},
+			},
+			"Statements": {
+				in:     "CREATE TABLE t (c INT);;\nCREATE TABLE u (d INT)",
+				tables: []string{"t", "u"}, columns: []string{"c"},
+			},
+			"Ignored": {
+				in: "LOCK TABLES `t` WRITE; CREATE TABLE t (c INT); UNLOCK TABLES; GRANT ALL ON *.* TO x;\n" +
+					"CREATE DEFINER=`root`@`localhost` TRIGGER x BEFORE INSERT ON t FOR EACH ROW SET @a = 1;\n" +
+					"CREATE USER x; ALTER DATABASE unknown CHARACTER SET latin1",
+				tables: []string{"t"}, columns: []string{"c"},
+			},
+			"Data": {
+				in: "SET NAMES utf8mb4; SET @@SESSION.SQL_LOG_BIN = 0; CREATE TABLE t (c INT);\n" +
+					"START TRANSACTION; INSERT INTO t VALUES (_binary 'a'), (0x01); COMMIT;",
+				tables: []string{"t"}, columns: []string{"c"},
+			},
+			"Syntax": {in: "CREATE TABLE t (c INT);\ngarbage()", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := mysql.Parse(strings.NewReader(tt.in))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			are.Equal(len(tt.tables), len(dbs[0].Tables)) // mismatch tables
+			for i, name := range tt.tables {
+				are.Equal(name, dbs[0].Tables[i].Name) // mismatch table name
+			}
+			are.Equal(len(tt.columns), len(dbs[0].Tables[0].Columns)) // mismatch columns
+			for i, name := range tt.columns {
+				are.Equal(name, dbs[0].Tables[0].Columns[i].Name) // mismatch column name
+			}
+		})
+	}
+}
+
+func TestParse_Definitions(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  string
+			err error
+		}{
+			"Closed":      {in: "CREATE TABLE t (id INT) -- ds: avg=2\n"},
+			"Empty":       {in: "CREATE TABLE (", err: ds.ErrInvalid},
+			"Column":      {in: "CREATE TABLE t (id INT", err: ds.ErrInvalid},
+			"LastColumn":  {in: "CREATE TABLE t (id INT, c VARCHAR(255)", err: ds.ErrInvalid},
+			"LineComment": {in: "CREATE TABLE t (c VARCHAR(255), -- ds: avg=24\n id INT", err: ds.ErrInvalid},
+			"Nested":      {in: "CREATE TABLE t (c DECIMAL(10, 2", err: ds.ErrInvalid},
+			"Alter":       {in: "CREATE TABLE t (id INT); ALTER TABLE t ADD COLUMN (c INT", err: ds.ErrInvalid},
+			"Index":       {in: "CREATE TABLE t (id INT); CREATE INDEX k ON t ((abs(id))", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			_, err := mysql.Parse(strings.NewReader(tt.in))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+		})
+	}
+}
+
+func TestParse_Quotes(t *testing.T) {
+	const table = "CREATE TABLE t (id INT NOT NULL);\n"
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in      string
+			columns []string
+			err     error
+		}{
+			"Identifier":   {in: "ALTER TABLE t ADD COLUMN `a``b` INT", columns: []string{"id", "a`b"}},
+			"String":       {in: "ALTER TABLE t ADD COLUMN c INT COMMENT 'a''b\\''", columns: []string{"id", "c"}},
+			"Empty":        {in: "ALTER TABLE t ADD COLUMN c INT COMMENT ''", columns: []string{"id", "c"}},
+			"OpenQuote":    {in: "ALTER TABLE t ADD COLUMN `", err: ds.ErrInvalid},
+			"OpenString":   {in: "ALTER TABLE t ADD COLUMN c INT COMMENT '", err: ds.ErrInvalid},
+			"Truncated":    {in: "ALTER TABLE t ADD COLUMN c INT COMMENT 'ds: avg=2", err: ds.ErrInvalid},
+			"DoubledQuote": {in: "ALTER TABLE t ADD COLUMN c INT COMMENT 'a''", err: ds.ErrInvalid},
+			"Escaped":      {in: "ALTER TABLE t ADD COLUMN c INT COMMENT 'a\\'", err: ds.ErrInvalid},
+			"Table":        {in: "CREATE TABLE u (id INT) COMMENT \"a", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := mysql.Parse(strings.NewReader(table + tt.in))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			cols := dbs[0].Tables[0].Columns
+			are.Equal(len(tt.columns), len(cols)) // mismatch columns
+			for i, name := range tt.columns {
+				are.Equal(name, cols[i].Name) // mismatch column name
+			}
+		})
+	}
+}
+
+func TestParse_Versioned(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in         string
+			database   string
+			columns    []string
+			partitions int
+			err        error
+		}{
+			"Database": {
+				in:       "CREATE DATABASE /*!32312 IF NOT EXISTS*/ `shop` /*!40100 DEFAULT CHARACTER SET latin1 */; CREATE TABLE t (id INT)",
+				database: "shop", columns: []string{"id"},
+			},
+			"Partitioning": {
+				in:       "CREATE TABLE t (id INT) ENGINE=InnoDB /*!50100 PARTITION BY HASH (id) PARTITIONS 4 */",
+				database: "unknown", columns: []string{"id"}, partitions: 4,
+			},
+			"Column": {
+				in:       "CREATE TABLE t (id INT /*!80023 INVISIBLE */, /*!50700 c INT, */ d INT)",
+				database: "unknown", columns: []string{"id", "c", "d"},
+			},
+			"MariaDB": {
+				in:       "CREATE TABLE t (id INT) /*M!100100 PARTITION BY KEY () PARTITIONS 2 */",
+				database: "unknown", columns: []string{"id"}, partitions: 2,
+			},
+			"Delimiter": {
+				in: "CREATE TABLE t (id INT);\nDELIMITER ;;\n" +
+					"/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`localhost`*/ /*!50003 TRIGGER x BEFORE INSERT ON t " +
+					"FOR EACH ROW BEGIN SET NEW.id = 1; END */;;\nDELIMITER ;\nALTER TABLE t ADD c INT",
+				database: "unknown", columns: []string{"id", "c"},
+			},
+			"Word": {
+				in: "delimiter $$\nCREATE PROCEDURE p() BEGIN SELECT 1; END$$\nCREATE TABLE t (id INT)$$\n" +
+					"DELIMITER ;\nALTER TABLE t ADD c INT;",
+				database: "unknown", columns: []string{"id", "c"},
+			},
+			"Unclosed": {in: "CREATE TABLE t (id INT) /*!50100 PARTITION BY HASH (id", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := mysql.Parse(strings.NewReader(tt.in))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			are.Equal(tt.database, dbs[0].Name) // mismatch database
+			tb := dbs[0].Tables[0]
+			are.Equal(len(tt.columns), len(tb.Columns)) // mismatch columns
+			for i, name := range tt.columns {
+				are.Equal(name, tb.Columns[i].Name) // mismatch column name
+			}
+			are.Equal(tt.partitions, len(tb.Partitioning.Partitions)) // mismatch partitions
+		})
+	}
+}
+
+func TestParse_Dump(t *testing.T) {
+	are := is.New(t)
+	f, err := os.Open("../../testdata/mysql/dump.sql")
+	are.NoErr(err) // unexpected open error
+	defer func() { _ = f.Close() }()
+	dbs, err := mysql.Parse(f)
+	are.NoErr(err)                      // unexpected parse error
+	are.Equal(1, len(dbs))              // mismatch databases
+	are.Equal("shop", dbs[0].Name)      // mismatch database
+	are.Equal("latin1", dbs[0].Charset) // mismatch charset
+	are.Equal(1, len(dbs[0].Tables))    // mismatch tables
+	tb := dbs[0].Tables[0]
+	are.Equal("orders", tb.Name)                            // mismatch table
+	are.Equal(3, len(tb.Columns))                           // mismatch columns
+	are.Equal(mysql.RangePartition, tb.Partitioning.Method) // mismatch partitioning method
+	are.Equal(3, len(tb.Partitioning.Partitions))           // mismatch partitions
+}
+
+func TestEstimator_Parse_Syntax(t *testing.T) {
+	const in = "CREATE TABLE t (id INT);\nDELIMITER ;;\nCREATE TABLE u (id INT;;\nDELIMITER ;\n" +
+		"garbage(); CREATE TABLE v (id INT) COMMENT 'a"
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			strict bool
+			tables int
+			out    string
+			err    error
+		}{
+			"Default": {
+				tables: 1,
+				out: "warning: statement \"CREATE TABLE u (id INT\": unclosed parenthesis: invalid data (skipped)\n" +
+					"warning: statement \"garbage()\": syntax error at position 11 near 'garbage': invalid data (skipped)\n" +
+					"warning: statement \"CREATE TABLE v (id INT) COMMENT 'a\": unterminated quoted string: " +
+					"invalid data (skipped)\n",
+			},
+			"Strict": {strict: true, err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			e, err := mysql.Estimate(mysql.SetStrictMode(tt.strict), mysql.SetDiagnosticOutput(buf))
+			are.NoErr(err) // unexpected estimator error
+			dbs, err := e.Parse(strings.NewReader(in))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(tt.out, buf.String())  // mismatch warnings
+			if tt.err != nil {
+				return
+			}
+			are.Equal(tt.tables, len(dbs[0].Tables)) // mismatch tables
+		})
+	}
+}
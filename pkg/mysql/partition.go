@@ -0,0 +1,318 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// PartitionMethod is a partitioning method.
+type PartitionMethod string
+
+// List of supported partitioning methods.
+const (
+	HashPartition  = PartitionMethod("hash")
+	KeyPartition   = PartitionMethod("key")
+	ListPartition  = PartitionMethod("list")
+	RangePartition = PartitionMethod("range")
+)
+
+// ToPartitionMethod returns a partitioning method based on the given name.
+func ToPartitionMethod(s string) PartitionMethod {
+	switch m := PartitionMethod(strings.ToLower(s)); m {
+	case HashPartition, KeyPartition, ListPartition, RangePartition:
+		return m
+	default:
+		return ""
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (m PartitionMethod) String() string {
+	return string(m)
+}
+
+// Partitioning represents the partitioning of a table.
+type Partitioning struct {
+	Method     PartitionMethod
+	Partitions []Partition
+}
+
+// Partition is a table's partition.
+// Weight is used to distribute the rows across the partitions.
+// Subpartitions is the number of its subpartitions, each one stored in its own tablespace, zero if none.
+type Partition struct {
+	Name          string
+	Weight        uint64
+	Subpartitions uint64
+}
+
+// DefaultPartitionWeight is the weight of a partition without declared one.
+const DefaultPartitionWeight = 1
+
+// weights returns the sum of the weights of the partitions.
+func (p Partitioning) weights() (sum uint64) {
+	for _, v := range p.Partitions {
+		sum += v.Weight
+	}
+	return
+}
+
+// tablespaces returns the number of tablespaces of the partitions: one by partition or subpartition.
+func (p Partitioning) tablespaces() (sum uint64) {
+	for _, v := range p.Partitions {
+		sum += v.tablespaces()
+	}
+	return
+}
+
+// tablespaces returns the number of tablespaces of the partition: one, or one by subpartition.
+func (p Partition) tablespaces() uint64 {
+	if p.Subpartitions == 0 {
+		return 1
+	}
+	return p.Subpartitions
+}
+
+// rows returns the number of rows in the partition at the given position
+// when the table contains n rows.
+func (p Partitioning) rows(pos int, n uint64) uint64 {
+	var (
+		total = p.weights()
+		lower uint64
+	)
+	if total == 0 {
+		return 0
+	}
+	for _, v := range p.Partitions[:pos] {
+		lower += v.Weight
+	}
+	upper := lower + p.Partitions[pos].Weight
+	return share(n, upper, total) - share(n, lower, total)
+}
+
+// share returns n * weight / total, without overflowing, the weight being at most the total.
+func share(n, weight, total uint64) uint64 {
+	hi, lo := bits.Mul64(n, weight)
+	q, _ := bits.Div64(hi, lo, total)
+	return q
+}
+
+// Partitions returns the partitions properties.
+func (t Table) Partitions() []ds.Data {
+	res := make([]ds.Data, len(t.Partitioning.Partitions))
+	for p := range t.Partitioning.Partitions {
+		res[p] = partition{table: t, pos: p}
+	}
+	return res
+}
+
+// partitioned returns true if the table is partitioned.
+func (t Table) partitioned() bool {
+	return len(t.Partitioning.Partitions) > 0
+}
+
+// overhead returns the size used by the tablespace of a partition, regardless of its number of rows.
+func (t Table) overhead() (min, max uint64) {
+	return t.Engine.PartitionOverhead(len(t.Indexes), t.PageSize)
+}
+
+// Scale implements the ds.Scaler interface.
+// Each partition, or subpartition, adds the fixed overhead of its tablespace to the size of the table.
+func (t Table) Scale(rows uint64) (min, max uint64) {
+	min, max = t.Size()
+	min, max = ds.Mul(min, rows), ds.Mul(max, rows)
+	if !t.partitioned() {
+		return
+	}
+	on, ox := t.overhead()
+	p := t.Partitioning.tablespaces()
+	return ds.Add(min, ds.Mul(on, p)), ds.Add(max, ds.Mul(ox, p))
+}
+
+// partition represents the data of a table's partition.
+type partition struct {
+	table Table
+	pos   int
+}
+
+// Kind implements the ds.Data interface.
+func (p partition) Kind() string {
+	var (
+		w = p.table.Partitioning.Partitions[p.pos].Weight
+		t = p.table.Partitioning.weights()
+	)
+	return fmt.Sprintf("partition(%s, %d/%d)", p.table.Partitioning.Method, w, t)
+}
+
+// Size implements the ds.Data interface.
+// A row stored in a partition has the same size as in the table.
+func (p partition) Size() (min, max uint64) {
+	return p.table.Size()
+}
+
+// Distribution implements the ds.Distributor interface.
+func (p partition) Distribution() ds.Distribution {
+	return p.table.Distribution()
+}
+
+// Scale implements the ds.Scaler interface.
+func (p partition) Scale(rows uint64) (min, max uint64) {
+	min, max = p.Size()
+	var (
+		r      = p.table.Partitioning.rows(p.pos, rows)
+		n      = p.table.Partitioning.Partitions[p.pos].tablespaces()
+		on, ox = p.table.overhead()
+	)
+	return ds.Add(ds.Mul(min, r), ds.Mul(on, n)), ds.Add(ds.Mul(max, r), ds.Mul(ox, n))
+}
+
+// String implements the ds.Data interface.
+func (p partition) String() string {
+	return p.table.Partitioning.Partitions[p.pos].Name
+}
+
+// partitioning parses the partitioning clause of the CREATE TABLE statement, if exists.
+// It returns the statement without this clause, not supported by the SQL parser.
+// See https://dev.mysql.com/doc/refman/8.0/en/create-table.html#create-table-partitioning
+func partitioning(stmt tokens) (tokens, Partitioning, error) {
+	var res Partitioning
+	pos, depth := 0, 0
+	for ; pos < len(stmt); pos++ {
+		if stmt[pos].is("(") {
+			depth++
+		} else if stmt[pos].is(")") {
+			depth--
+		}
+		if _, ok := stmt.words(pos, "partition", "by"); ok && depth == 0 {
+			break
+		}
+	}
+	if pos == len(stmt) {
+		return stmt, res, nil
+	}
+	clause := stmt[pos:]
+	stmt = stmt[:pos]
+
+	cur, _ := clause.words(0, "partition", "by")
+	cur = clause.next(cur)
+	if clause.at(cur).is("linear") {
+		cur = clause.next(cur)
+	}
+	res.Method = ToPartitionMethod(clause.at(cur).val)
+	if res.Method == "" {
+		return nil, res, ds.WrapErr("table partitioning method", ds.ErrInvalid)
+	}
+	var (
+		num  uint64 = 1
+		subs uint64
+		err  error
+	)
+	for cur = clause.next(cur); cur < len(clause); cur = clause.next(cur) {
+		switch t := clause.at(cur); {
+		case t.is("partitions"):
+			cur = clause.next(cur)
+			num, err = strconv.ParseUint(clause.at(cur).val, base10, bits64)
+			if err != nil || num == 0 {
+				return nil, res, ds.WrapErr("table partitions number", ds.ErrInvalid)
+			}
+		case t.is("subpartition"):
+			// Subpartitions are considered as part of their partition, except for their tablespace.
+			for ; cur < len(clause) && !clause.at(cur).is("("); cur = clause.next(cur) {
+			}
+			if cur = clause.closing(cur); cur == len(clause) {
+				return nil, res, syntaxError(clause, errUnclosed)
+			}
+			subs = 1
+		case t.is("subpartitions"):
+			cur = clause.next(cur)
+			subs, err = strconv.ParseUint(clause.at(cur).val, base10, bits64)
+			if err != nil || subs == 0 {
+				return nil, res, ds.WrapErr("table subpartitions number", ds.ErrInvalid)
+			}
+		case t.is("("):
+			defs, end := clause.list(cur)
+			if end == len(clause) {
+				return nil, res, syntaxError(clause, errUnclosed)
+			}
+			if _, ok := clause.words(clause.next(cur), "partition"); !ok {
+				// Partitioning expression or columns list.
+				cur = end
+				continue
+			}
+			for _, def := range defs {
+				p, err := partitionDefinition(def, subs)
+				if err != nil {
+					return nil, res, err
+				}
+				res.Partitions = append(res.Partitions, p)
+			}
+			if res.weights() == 0 {
+				// No row could be distributed.
+				return nil, res, ds.WrapErr("table partition weights", ds.ErrInvalid)
+			}
+			return stmt, res, nil
+		}
+	}
+	if res.Method == RangePartition || res.Method == ListPartition {
+		return nil, res, ds.WrapErr("table partition definition", ds.ErrMissing)
+	}
+	for i := uint64(0); i < num; i++ {
+		res.Partitions = append(res.Partitions, Partition{
+			Name:          "p" + strconv.FormatUint(i, base10),
+			Weight:        DefaultPartitionWeight,
+			Subpartitions: subs,
+		})
+	}
+	return stmt, res, nil
+}
+
+// partitionDefinition parses a definition, like: PARTITION p0 VALUES LESS THAN (1990) COMMENT 'ds: weight=3',
+// with this number of subpartitions, unless it defines its own ones.
+func partitionDefinition(def tokens, subs uint64) (Partition, error) {
+	pos, _ := def.words(0, "partition")
+	pos = def.next(pos)
+	res := Partition{
+		Name:          def.at(pos).text(),
+		Weight:        DefaultPartitionWeight,
+		Subpartitions: subs,
+	}
+	for ; pos < len(def); pos = def.next(pos) {
+		switch {
+		case def.at(pos).is("("):
+			if _, ok := def.words(def.next(pos), "subpartition"); ok {
+				list, _ := def.list(pos)
+				res.Subpartitions = uint64(len(list))
+			}
+			pos = def.closing(pos)
+		case def.at(pos).is("comment"):
+			pos = def.next(pos)
+			if def.at(pos).is(equal) {
+				pos = def.next(pos)
+			}
+			w, ok := annotations(def.at(pos).text())[annotationWeight]
+			if !ok {
+				continue
+			}
+			var err error
+			res.Weight, err = strconv.ParseUint(w, base10, bits64)
+			if err != nil {
+				return res, ds.WrapErr("partition weight", ds.ErrInvalid)
+			}
+		}
+	}
+	if res.Name == "" {
+		return res, ds.WrapErr("partition name", ds.ErrMissing)
+	}
+	return res, nil
+}
+
+// annotationWeight is the annotation used to declare the weight of a partition.
+const annotationWeight = "weight"
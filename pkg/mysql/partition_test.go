@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestParse_Partitioning(t *testing.T) {
+	const (
+		create   = "CREATE TABLE t (id INT NOT NULL) PARTITION BY "
+		overhead = 81920
+	)
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in      string
+			names   []string
+			weights []uint64
+			subs    []uint64
+			min     uint64
+			err     error
+		}{
+			"Hash": {
+				in: create + "HASH (id) PARTITIONS 2", names: []string{"p0", "p1"},
+				weights: []uint64{1, 1}, subs: []uint64{0, 0}, min: 400 + 2*overhead,
+			},
+			"LinearKey": {
+				in: create + "LINEAR KEY (id) PARTITIONS 3", names: []string{"p0", "p1", "p2"},
+				weights: []uint64{1, 1, 1}, subs: []uint64{0, 0, 0}, min: 400 + 3*overhead,
+			},
+			"Range": {
+				in: create + "RANGE (id) (PARTITION a VALUES LESS THAN (10) COMMENT 'ds: weight=3', " +
+					"PARTITION `b` VALUES LESS THAN MAXVALUE)",
+				names: []string{"a", "b"}, weights: []uint64{3, 1}, subs: []uint64{0, 0}, min: 400 + 2*overhead,
+			},
+			"EmptyPartition": {
+				in:    create + "LIST (id) (PARTITION a VALUES IN (1) COMMENT 'ds: weight=0', PARTITION b VALUES IN (2))",
+				names: []string{"a", "b"}, weights: []uint64{0, 1}, subs: []uint64{0, 0}, min: 400 + 2*overhead,
+			},
+			"Subpartitions": {
+				in: create + "RANGE (id) SUBPARTITION BY HASH (id) SUBPARTITIONS 2 " +
+					"(PARTITION a VALUES LESS THAN (10), PARTITION b VALUES LESS THAN MAXVALUE)",
+				names: []string{"a", "b"}, weights: []uint64{1, 1}, subs: []uint64{2, 2}, min: 400 + 4*overhead,
+			},
+			"SubpartitionDefinitions": {
+				in: create + "RANGE (id) SUBPARTITION BY KEY (id) " +
+					"(PARTITION a VALUES LESS THAN (10) (SUBPARTITION s0, SUBPARTITION s1, SUBPARTITION s2), " +
+					"PARTITION b VALUES LESS THAN MAXVALUE (SUBPARTITION s3))",
+				names: []string{"a", "b"}, weights: []uint64{1, 1}, subs: []uint64{3, 1}, min: 400 + 4*overhead,
+			},
+			"Method":       {in: create + "COLUMNS (id) PARTITIONS 2", err: ds.ErrInvalid},
+			"Number":       {in: create + "HASH (id) PARTITIONS 0", err: ds.ErrInvalid},
+			"SubNumber":    {in: create + "RANGE (id) SUBPARTITION BY HASH (id) SUBPARTITIONS x", err: ds.ErrInvalid},
+			"Definition":   {in: create + "RANGE (id)", err: ds.ErrMissing},
+			"Weight":       {in: create + "LIST (id) (PARTITION a VALUES IN (1) COMMENT 'ds: weight=x')", err: ds.ErrInvalid},
+			"ZeroWeights":  {in: create + "LIST (id) (PARTITION a VALUES IN (1) COMMENT 'ds: weight=0')", err: ds.ErrInvalid},
+			"Unclosed":     {in: create + "HASH (id", err: ds.ErrInvalid},
+			"Partitions":   {in: create + "RANGE (id) (PARTITION a VALUES LESS THAN (10)", err: ds.ErrInvalid},
+			"Subpartition": {in: create + "RANGE (id) SUBPARTITION BY HASH (id", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := mysql.Parse(strings.NewReader(tt.in))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			p := dbs[0].Tables[0].Partitioning
+			are.Equal(len(tt.names), len(p.Partitions)) // mismatch partitions
+			var (
+				sum   uint64
+				parts = dbs[0].Tables[0].Partitions()
+			)
+			for i, v := range p.Partitions {
+				are.Equal(tt.names[i], v.Name)         // mismatch name
+				are.Equal(tt.weights[i], v.Weight)     // mismatch weight
+				are.Equal(tt.subs[i], v.Subpartitions) // mismatch subpartitions
+				n, _ := ds.Scale(parts[i], 100)
+				sum += n
+			}
+			n, _ := dbs[0].Tables[0].Scale(100)
+			are.Equal(tt.min, n) // mismatch table size
+			are.Equal(n, sum)    // partitions must add up to the table
+		})
+	}
+}
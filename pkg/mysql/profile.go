@@ -0,0 +1,201 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Profile describes the values of a column, when they are known.
+type Profile struct {
+	// Average is the average length in bytes of the values of a variable data type, zero if unknown.
+	Average uint64
+	// NullRatio is the ratio of NULL values, between 0 and 1.
+	NullRatio float64
+}
+
+// Annotations used to describe the profile of a column.
+const (
+	annotationAverage = "avg"
+	annotationNull    = "null"
+)
+
+// Profiled returns true if the profile describes the values.
+func (p Profile) Profiled() bool {
+	return p.Average > 0 || p.NullRatio > 0
+}
+
+// toProfile returns the profile declared by the annotations, like "ds: avg=24 null=0.1".
+func toProfile(a map[string]string) (Profile, error) {
+	var (
+		p   Profile
+		err error
+	)
+	if v, ok := a[annotationAverage]; ok {
+		p.Average, err = strconv.ParseUint(v, base10, bits64)
+		if err != nil {
+			return p, ds.WrapErr("profile average length", ds.ErrInvalid)
+		}
+	}
+	if v, ok := a[annotationNull]; ok {
+		p.NullRatio, err = strconv.ParseFloat(v, bits64)
+		if err != nil || p.NullRatio < 0 || p.NullRatio > 1 {
+			return p, ds.WrapErr("profile null ratio", ds.ErrInvalid)
+		}
+	}
+	return p, nil
+}
+
+// columnProfile returns the profile declared in the comments of the column definition,
+// like COMMENT 'ds: avg=24' or -- ds: avg=24 null=0.1.
+// The line comment may follow the comma ending the definition, so the next definition is also given,
+// only its comments before its first line break being used.
+func columnProfile(def, next tokens) (Profile, error) {
+	var a map[string]string
+	merge := func(s string) {
+		for k, v := range annotations(s) {
+			if a == nil {
+				a = make(map[string]string)
+			}
+			a[k] = v
+		}
+	}
+	for pos := def.next(-1); pos < len(def); pos++ {
+		switch t := def[pos]; {
+		case t.typ == commentToken:
+			merge(t.val)
+		case t.is("comment"):
+			if pos = def.next(pos); def.at(pos).is(equal) {
+				pos = def.next(pos)
+			}
+			merge(def.at(pos).text())
+		}
+	}
+	for _, t := range next {
+		if t.typ == commentToken {
+			merge(t.val)
+			continue
+		}
+		if t.typ != spaceToken || strings.Contains(t.val, "\n") {
+			break
+		}
+	}
+	return toProfile(a)
+}
+
+// Profiles lists column profiles by their full name, like db.table.column.
+type Profiles map[string]Profile
+
+// ReadProfiles reads column profiles, one by line, like: db.table.column avg=24 null=0.1.
+// Empty lines and lines starting with # are ignored.
+func ReadProfiles(r io.Reader) (Profiles, error) {
+	var (
+		res = make(Profiles)
+		s   = bufio.NewScanner(r)
+	)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		f := strings.Fields(line)
+		p, err := toProfile(annotations(annotation + strings.Join(f[1:], space)))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		res[strings.ToLower(f[0])] = p
+	}
+	return res, s.Err()
+}
+
+// profile returns the profile of the column of this table in the database, if any.
+func (p Profiles) profile(db, table, column string) (Profile, bool) {
+	v, ok := p[strings.ToLower(db+"."+table+"."+column)]
+	return v, ok
+}
+
+// setProfile sets the profile of the column, and of the key parts using it.
+func (t *Table) setProfile(column string, p Profile) {
+	for i := range t.Columns {
+		if t.Columns[i].Name == column {
+			t.Columns[i].Profile = p
+		}
+	}
+	for i := range t.Indexes {
+		for j := range t.Indexes[i].Parts {
+			if t.Indexes[i].Parts[j].Name == column {
+				t.Indexes[i].Parts[j].Profile = p
+			}
+		}
+	}
+}
+
+// nullable returns true if the column contains NULL values.
+func (c Column) nullable() bool {
+	return !c.NotNull && c.Profile.NullRatio > 0
+}
+
+// Distribution implements the ds.Distributor interface.
+// With a profile, the size of a value of a variable data type is its average length, and a NULL value has no size.
+func (c Column) Distribution() ds.Distribution {
+	min, max := c.valueSize()
+	return c.Profile.distribution(min, max, c.DataType.IsVar() && !c.DataType.IsSpatial(), c.nullable())
+}
+
+// distribution returns the distribution of a value between min and max bytes, the minimum being the bytes
+// storing its length when variable.
+func (p Profile) distribution(min, max uint64, variable, nullable bool) ds.Distribution {
+	d := ds.Uniform(min, max)
+	if variable && p.Average > 0 {
+		size := ds.Add(min, p.Average)
+		if size > max {
+			size = max
+		}
+		d = ds.Fixed(size)
+	}
+	if !nullable {
+		return d
+	}
+	// Mixture between NULL values, without size, and the others.
+	var (
+		q    = 1 - p.NullRatio
+		mean = q * d.Mean
+	)
+	return ds.Distribution{
+		Mean:     mean,
+		Variance: q*(d.Variance+d.Mean*d.Mean) - mean*mean,
+	}
+}
+
+// nullStorage returns true if a NULL value of this data type still uses data bytes with this row format.
+// See https://dev.mysql.com/doc/refman/8.0/en/innodb-row-format.html
+func (e Engine) nullStorage(d DataType, cur RowFormat) bool {
+	switch e {
+	case InnoDB:
+		return cur == RedundantRowFormat && !d.IsVar()
+	case MyISAM:
+		return cur != DynamicRowFormat
+	default:
+		return true
+	}
+}
+
+// nulls returns the columns, ignoring their NULL ratio if their NULL values use data bytes.
+func (e Engine) nulls(cols []Column, cur RowFormat) []Column {
+	res := make([]Column, len(cols))
+	for p, c := range cols {
+		if c.nullable() && e.nullStorage(c.DataType, cur) {
+			c.Profile.NullRatio = 0
+		}
+		res[p] = c
+	}
+	return res
+}
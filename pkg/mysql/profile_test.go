@@ -0,0 +1,119 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestReadProfiles(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  string
+			out mysql.Profiles
+			err error
+		}{
+			"Default": {out: mysql.Profiles{}},
+			"Comments": {
+				in:  "# profiles\n\nshop.user.bio avg=480 null=0.9\n  Shop.User.Name avg=12\n",
+				out: mysql.Profiles{"shop.user.bio": {Average: 480, NullRatio: 0.9}, "shop.user.name": {Average: 12}},
+			},
+			"Average": {in: "shop.user.bio avg=x", err: ds.ErrInvalid},
+			"Null":    {in: "shop.user.bio null=2", err: ds.ErrInvalid},
+			"Line":    {in: "shop.user.bio avg=2\nshop.user.name null=x", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			out, err := mysql.ReadProfiles(strings.NewReader(tt.in))
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err == nil {
+				are.Equal(tt.out, out) // mismatch profiles
+			}
+		})
+	}
+	_, err := mysql.ReadProfiles(failReader{})
+	are.True(errors.Is(err, ds.ErrProcess)) // expected read error
+}
+
+// failReader is a reader always in error.
+type failReader struct{}
+
+func (failReader) Read([]byte) (int, error) {
+	return 0, ds.ErrProcess
+}
+
+func TestEstimator_Parse_Profiles(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in      string
+			opts    []mysql.Configurator
+			profile mysql.Profile
+			mean    float64
+			err     error
+		}{
+			"Default": {in: "c VARCHAR(255) NOT NULL", mean: 512},
+			"Comment": {
+				in:      "c VARCHAR(255) NOT NULL COMMENT 'ds: avg=24'",
+				profile: mysql.Profile{Average: 24}, mean: 26,
+			},
+			"LineComment": {
+				in:      "c VARCHAR(255) NOT NULL, -- ds: avg=24\n id INT",
+				profile: mysql.Profile{Average: 24}, mean: 26,
+			},
+			"Merged": {
+				in:      "c VARCHAR(64) COMMENT 'ds: avg=12 null=0.5' -- ds: null=0.25\n",
+				profile: mysql.Profile{Average: 12, NullRatio: 0.25}, mean: 10.5,
+			},
+			"NextLine": {
+				in:   "c VARCHAR(255) NOT NULL,\n id INT -- ds: avg=24\n",
+				mean: 512,
+			},
+			"Average": {
+				in:      "c VARCHAR(255) NOT NULL COMMENT 'ds: avg=2000'",
+				profile: mysql.Profile{Average: 2000}, mean: 1022,
+			},
+			"Fixed": {
+				in:      "c CHAR(10) NOT NULL COMMENT 'ds: avg=2'",
+				profile: mysql.Profile{Average: 2}, mean: 10,
+			},
+			"NotNull": {
+				in:      "c INT NOT NULL COMMENT 'ds: null=0.5'",
+				profile: mysql.Profile{NullRatio: 0.5}, mean: 4,
+			},
+			"File": {
+				in:      "c VARCHAR(255) NOT NULL COMMENT 'ds: avg=24'",
+				opts:    []mysql.Configurator{mysql.SetProfiles(mysql.Profiles{"unknown.t.c": {Average: 100}})},
+				profile: mysql.Profile{Average: 100}, mean: 102,
+			},
+			"InvalidAverage": {in: "c VARCHAR(255) COMMENT 'ds: avg=x'", err: ds.ErrInvalid},
+			"InvalidNull":    {in: "c VARCHAR(255) COMMENT 'ds: null=-1'", err: ds.ErrInvalid},
+			"InvalidLine":    {in: "c VARCHAR(255), -- ds: null=2\n id INT", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := parse("CREATE TABLE t ("+tt.in+")", tt.opts...)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			c := dbs[0].Tables[0].Columns[0]
+			are.Equal(tt.profile, c.Profile)                         // mismatch profile
+			are.True(math.Abs(tt.mean-c.Distribution().Mean) < 1e-9) // mismatch mean size
+		})
+	}
+}
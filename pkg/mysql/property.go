@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import "github.com/rvflash/ds/pkg/ds/render"
+
+// List of properties of the data, available as columns of the report.
+const (
+	// EngineProperty is the engine of a table.
+	EngineProperty = "engine"
+	// CharsetProperty is the charset of a database, a table or a string column.
+	CharsetProperty = "charset"
+	// NullableProperty is YES if a column can contain NULL values, NO otherwise.
+	NullableProperty = "nullable"
+)
+
+// Columns returns the columns that can be displayed in the report.
+func Columns() []render.Column {
+	return append(render.DefaultColumns(), EngineProperty, CharsetProperty, NullableProperty)
+}
+
+// Values of the nullable property.
+const (
+	yes = "YES"
+	no  = "NO"
+)
+
+// Property implements the ds.Describer interface.
+func (c Column) Property(name string) string {
+	switch name {
+	case CharsetProperty:
+		if c.DataType.IsString() {
+			return c.Charset
+		}
+	case NullableProperty:
+		if c.NotNull {
+			return no
+		}
+		return yes
+	}
+	return ""
+}
+
+// Property implements the ds.Describer interface.
+func (t Table) Property(name string) string {
+	switch name {
+	case EngineProperty:
+		return t.Engine.String()
+	case CharsetProperty:
+		return Charset(t.Charset)
+	}
+	return ""
+}
+
+// Property implements the ds.Describer interface.
+func (d Database) Property(name string) string {
+	if name == CharsetProperty {
+		return Charset(d.Charset)
+	}
+	return ""
+}
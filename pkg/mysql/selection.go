@@ -0,0 +1,96 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"path"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// selection returns the databases to render, with only the tables matching the filters,
+// sorted and limited to the top N by database, if requested.
+// The databases without table to render are skipped.
+func (e *Estimator) selection(dbs Storage) []ds.Data {
+	res := make([]ds.Data, 0, len(dbs))
+	for _, d := range dbs {
+		if !match(e.databasePattern, d.Name) {
+			continue
+		}
+		tables := make([]ds.Data, 0, len(d.Tables))
+		for _, t := range d.Tables {
+			if !match(e.tablePattern, t.Name) || (e.engine != "" && t.Engine != e.engine) {
+				continue
+			}
+			tables = append(tables, sortedTable{Table: t, order: e.order, rows: e.perN})
+		}
+		if len(tables) == 0 && len(d.Tables) > 0 {
+			continue
+		}
+		order := e.order
+		if e.top > 0 && order == ds.Unsorted {
+			// The top N tables are the largest ones.
+			order = ds.ByMaxSize
+		}
+		order.Sort(tables, e.perN)
+		if e.top > 0 && uint64(len(tables)) > e.top {
+			tables = tables[:e.top]
+		}
+		res = append(res, selectedDatabase{Database: d.only(tables), tables: tables})
+	}
+	return res
+}
+
+// match returns true if the name matches the pattern, an empty one matching any name.
+func match(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// selectedDatabase is a database limited to the tables to render.
+type selectedDatabase struct {
+	Database
+	tables []ds.Data
+}
+
+// Children implements the ds.Node interface.
+func (d selectedDatabase) Children() []ds.Data {
+	return d.tables
+}
+
+// only returns a copy of the database limited to these tables.
+func (d Database) only(tables []ds.Data) Database {
+	res := Database{Name: d.Name, Charset: d.Charset, Tables: make([]Table, len(tables))}
+	for p, t := range tables {
+		res.Tables[p] = t.(sortedTable).Table
+	}
+	return res
+}
+
+// sortedTable is a table whose columns and keys are sorted by size.
+type sortedTable struct {
+	Table
+	order ds.Order
+	rows  uint64
+}
+
+// Children implements the ds.Node interface.
+func (t sortedTable) Children() []ds.Data {
+	fields, keys := t.Fields(), t.Keys()
+	t.order.Sort(fields, t.rows)
+	t.order.Sort(keys, t.rows)
+	return append(append(fields, keys...), t.Partitions()...)
+}
+
+// Groups implements the ds.Grouper interface.
+func (t sortedTable) Groups() []ds.Node {
+	fields, keys := t.Fields(), t.Keys()
+	t.order.Sort(fields, t.rows)
+	t.order.Sort(keys, t.rows)
+	return t.groups(fields, keys)
+}
@@ -0,0 +1,293 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Interact runs an interactive session: it reads the SQL statements line by line, like CREATE, ALTER, DROP
+// or USE ones, applies them on the storage kept in memory, and after each of them, prints the size delta
+// of the changed table and of its database. A statement in error is ignored, the session going on.
+//
+// The lines starting with a dot are the commands of the session:
+//   - .report renders the estimation of the storage, as Run,
+//   - .reset drops all the databases,
+//   - .rows changes the numbers of rows to estimate, like .rows 1e6,1e9,
+//   - .help lists the commands and .quit ends the session, as the end of the reader.
+func (e *Estimator) Interact(r io.Reader, w io.Writer) error {
+	s := &session{Estimator: e, schema: e.emptySchema(), w: w}
+	return s.run(r)
+}
+
+// emptySchema returns an empty schema with the default charset and engine of the estimator.
+func (e *Estimator) emptySchema() *schema {
+	return newSchema(defaults{charset: e.charset, engine: e.defaultEngine})
+}
+
+// session is an interactive session of the estimator.
+type session struct {
+	*Estimator
+	*schema
+	w io.Writer
+}
+
+// Prompts of the session, to read a new statement or the next line of the current one.
+const (
+	prompt     = "ds> "
+	nextPrompt = "  -> "
+)
+
+// Commands of the session.
+const (
+	helpCommand   = ".help"
+	quitCommand   = ".quit"
+	reportCommand = ".report"
+	resetCommand  = ".reset"
+	rowsCommand   = ".rows"
+)
+
+func (s *session) run(r io.Reader) error {
+	var (
+		buf string
+		sc  = bufio.NewScanner(r)
+	)
+	for {
+		p := prompt
+		if buf != "" {
+			p = nextPrompt
+		}
+		if _, err := fmt.Fprint(s.w, p); err != nil {
+			return err
+		}
+		if !sc.Scan() {
+			break
+		}
+		line := sc.Text()
+		if buf == "" && strings.HasPrefix(strings.TrimSpace(line), ".") {
+			quit, err := s.command(strings.Fields(line))
+			if quit || err != nil {
+				return err
+			}
+			continue
+		}
+		stmts := lex(buf + line + "\n").split()
+		for _, ts := range stmts[:len(stmts)-1] {
+			if err := s.exec(ts); err != nil {
+				return err
+			}
+		}
+		buf = ""
+		if last := stmts[len(stmts)-1]; !last.empty() {
+			buf = last.String()
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(s.w); err != nil {
+		return err
+	}
+	// The last statement may not end with a semicolon.
+	return s.exec(lex(buf))
+}
+
+// command runs the command of the session. It returns true to end the session.
+func (s *session) command(args []string) (quit bool, err error) {
+	switch strings.ToLower(args[0]) {
+	case quitCommand:
+		return true, nil
+	case helpCommand:
+		_, err = fmt.Fprintf(s.w, "%s\n%s\n%s\n%s\n%s\n",
+			reportCommand+"\t renders the estimation of the databases",
+			resetCommand+"\t drops all the databases",
+			rowsCommand+"\t changes the numbers of rows to estimate, like "+rowsCommand+" 1e6,1e9",
+			helpCommand+"\t lists the commands",
+			quitCommand+"\t ends the session",
+		)
+	case reportCommand:
+		err = s.Render(s.w, s.dbs)
+	case resetCommand:
+		s.schema = s.emptySchema()
+		_, err = fmt.Fprintln(s.w, "all the databases have been dropped")
+	case rowsCommand:
+		var rows ds.Scenarios
+		if len(args) < 2 {
+			return false, s.fail(ds.WrapErr("rows", ds.ErrMissing))
+		}
+		if err = rows.Set(strings.Join(args[1:], "")); err == nil {
+			err = SetScenarios(rows...)(s.Estimator)
+		}
+		if err != nil {
+			return false, s.fail(err)
+		}
+		_, err = fmt.Fprintf(s.w, "rows: %s\n", rows)
+	default:
+		return false, s.fail(ds.WrapErr("command "+args[0], ds.ErrInvalid))
+	}
+	return false, err
+}
+
+// exec applies the statement on the schema, then prints the size delta of the changed data.
+// If the statement fails, the schema is restored.
+func (s *session) exec(ts tokens) error {
+	if ts.empty() {
+		return nil
+	}
+	prev, cur := s.dbs.clone(), s.cur
+	c, err := s.schema.exec(ts)
+	if err == nil {
+		err = s.apply(c)
+	}
+	if err != nil {
+		s.dbs, s.cur = prev, cur
+		return s.fail(err)
+	}
+	err = s.delta(prev, c)
+	if err != nil || s.cur == cur {
+		return err
+	}
+	_, err = fmt.Fprintf(s.w, "database changed: %s\n", s.cur)
+	return err
+}
+
+// apply applies the estimator settings on the storage, and reports the diagnostics of the changed table.
+// In strict mode, the first of them is returned as error.
+func (s *session) apply(c change) error {
+	s.tune(s.dbs)
+	i, p, err := s.dbs.table(c.database, c.table)
+	if err != nil {
+		// Dropped or no table.
+		return nil
+	}
+	return s.diagnose(Storage{{Name: c.database, Tables: s.dbs[i].Tables[p : p+1]}})
+}
+
+func (s *session) fail(err error) error {
+	_, err = fmt.Fprintf(s.w, "error: %s\n", err)
+	return err
+}
+
+// delta prints the size delta of the changed table and of its database, since the previous storage.
+// A table moved to another database also changes the previous one.
+func (s *session) delta(prev Storage, c change) error {
+	dbs := []string{c.database}
+	if c.table != "" {
+		var (
+			verb   string
+			before = prev.tableData(c.database, c.table)
+		)
+		if c.fromTable != "" {
+			verb = "renamed from " + c.fromDatabase + "." + c.fromTable
+			before = prev.tableData(c.fromDatabase, c.fromTable)
+		}
+		err := s.printDelta(s.w, table, c.database+"."+c.table, verb, before, s.dbs.tableData(c.database, c.table))
+		if err != nil {
+			return err
+		}
+		if c.fromDatabase != "" && c.fromDatabase != c.database {
+			dbs = append([]string{c.fromDatabase}, dbs...)
+		}
+	}
+	for _, name := range dbs {
+		if name == "" {
+			continue
+		}
+		err := s.printDelta(s.w, db, name, "", prev.databaseData(name), s.dbs.databaseData(name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tableData returns the named table of the database, or nil if it does not exist.
+func (s Storage) tableData(dbName, name string) ds.Data {
+	i, p, err := s.table(dbName, name)
+	if err != nil {
+		return nil
+	}
+	return s[i].Tables[p]
+}
+
+// databaseData returns the named database, or nil if it does not exist.
+func (s Storage) databaseData(name string) ds.Data {
+	i, err := s.get(name)
+	if err != nil {
+		return nil
+	}
+	return s[i]
+}
+
+// printDelta prints the sizes of the data after the change with their delta, per row and for the number of rows.
+// Without verb, the change is described as a creation, a deletion or a change, based on the data.
+func (e *Estimator) printDelta(w io.Writer, kind, name, verb string, before, after ds.Data) error {
+	switch {
+	case before == nil && after == nil:
+		return nil
+	case verb != "":
+	case before == nil:
+		verb = "created"
+	case after == nil:
+		verb = "dropped"
+	default:
+		verb = "changed"
+	}
+	var (
+		b = newSizes(before, e.perN)
+		a = newSizes(after, e.perN)
+		f = ds.SizeFormat{Decimal: e.precision, Binary: e.binary, Unit: e.unit}
+	)
+	_, err := fmt.Fprintf(w, "%s %s %s: %s per row (%s - %s), %s for %d rows (%s - %s)\n",
+		kind, name, verb,
+		delta(f, b.min, b.max, a.min, a.max), f.Format(a.min), f.Format(a.max),
+		delta(f, b.minN, b.maxN, a.minN, a.maxN), e.perN, f.Format(a.minN), f.Format(a.maxN),
+	)
+	return err
+}
+
+// sizes are the sizes of a data, per row and for a number of rows.
+type sizes struct {
+	min, max, minN, maxN uint64
+}
+
+func newSizes(d ds.Data, rows uint64) sizes {
+	var res sizes
+	if d == nil {
+		return res
+	}
+	res.min, res.max = d.Size()
+	res.minN, res.maxN = ds.Scale(d, rows)
+	return res
+}
+
+// delta returns the signed differences between the minimum and maximum sizes,
+// only one if they are equal, like +1.20 KB or -8.00 B / +1.20 KB.
+func delta(f ds.SizeFormat, min, max, newMin, newMax uint64) string {
+	a, b := diff(f, min, newMin), diff(f, max, newMax)
+	if a == b {
+		return a
+	}
+	return a + " / " + b
+}
+
+func diff(f ds.SizeFormat, from, to uint64) string {
+	switch {
+	case from == to:
+		return "+" + f.Format(0)
+	case to == ds.Unbounded, from == ds.Unbounded:
+		// No meaningful difference with an unbounded size.
+		return "±" + f.Format(ds.Unbounded)
+	case from < to:
+		return "+" + f.Format(to-from)
+	default:
+		return "-" + f.Format(from-to)
+	}
+}
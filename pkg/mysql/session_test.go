@@ -0,0 +1,174 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestEstimator_Interact(t *testing.T) {
+	are := is.New(t)
+	e, err := mysql.Estimate(mysql.SetPrecision(0))
+	are.NoErr(err) // unexpected estimator error
+	var (
+		in = strings.Join([]string{
+			"CREATE TABLE t (",
+			"  id INT NOT NULL",
+			");",
+			"ALTER TABLE t ADD a INT NOT NULL; DROP TABLE x;",
+			".rows 1e3",
+			"DROP TABLE t;",
+			".report",
+			".unknown",
+			".reset",
+			"USE shop",
+		}, "\n")
+		out = []string{
+			"ds>   ->   -> table unknown.t created: +4 B per row (4 B - 4 B), +400 B for 100 rows (400 B - 400 B)",
+			"database unknown created: +4 B per row (4 B - 4 B), +400 B for 100 rows (400 B - 400 B)",
+			"ds> table unknown.t changed: +4 B per row (8 B - 8 B), +400 B for 100 rows (800 B - 800 B)",
+			"database unknown changed: +4 B per row (8 B - 8 B), +400 B for 100 rows (800 B - 800 B)",
+			"error: table: unknown.x: invalid data",
+			"ds> rows: 1000",
+			"ds> table unknown.t dropped: -8 B per row (0 B - 0 B), -8 KB for 1000 rows (0 B - 0 B)",
+			"database unknown changed: -8 B per row (0 B - 0 B), -8 KB for 1000 rows (0 B - 0 B)",
+		}
+		buf = new(bytes.Buffer)
+	)
+	are.NoErr(e.Interact(strings.NewReader(in), buf)) // unexpected session error
+	res := buf.String()
+	for _, s := range out {
+		are.True(strings.Contains(res, s+"\n")) // missing output
+	}
+	are.True(strings.Contains(res, "| unknown | database |"))                // missing report
+	are.True(strings.Contains(res, "error: command .unknown: invalid data")) // missing command error
+	are.True(strings.HasSuffix(res, "\ndatabase changed: shop\n"))           // missing last statement
+}
+
+func TestEstimator_Interact_Rename(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  string
+			out []string
+		}{
+			"Table": {
+				in: "RENAME TABLE t TO u",
+				out: []string{
+					"ds> table unknown.u renamed from unknown.t: +0 B per row (4 B - 4 B), +0 B for 100 rows (400 B - 400 B)",
+					"database unknown changed: +0 B per row (4 B - 4 B), +0 B for 100 rows (400 B - 400 B)",
+				},
+			},
+			"Alter": {
+				in: "ALTER TABLE t ADD a INT NOT NULL, RENAME TO u",
+				out: []string{
+					"ds> table unknown.u renamed from unknown.t: +4 B per row (8 B - 8 B), +400 B for 100 rows (800 B - 800 B)",
+					"database unknown changed: +4 B per row (8 B - 8 B), +400 B for 100 rows (800 B - 800 B)",
+				},
+			},
+			"TableMove": {
+				in: "CREATE DATABASE shop;\nRENAME TABLE unknown.t TO shop.u",
+				out: []string{
+					"ds> table shop.u renamed from unknown.t: +0 B per row (4 B - 4 B), +0 B for 100 rows (400 B - 400 B)",
+					"database unknown changed: -4 B per row (0 B - 0 B), -400 B for 100 rows (0 B - 0 B)",
+					"database shop changed: +4 B per row (4 B - 4 B), +400 B for 100 rows (400 B - 400 B)",
+				},
+			},
+			"AlterMove": {
+				in: "CREATE DATABASE shop;\nALTER TABLE unknown.t RENAME shop.u",
+				out: []string{
+					"ds> table shop.u renamed from unknown.t: +0 B per row (4 B - 4 B), +0 B for 100 rows (400 B - 400 B)",
+					"database unknown changed: -4 B per row (0 B - 0 B), -400 B for 100 rows (0 B - 0 B)",
+					"database shop changed: +4 B per row (4 B - 4 B), +400 B for 100 rows (400 B - 400 B)",
+				},
+			},
+			"Exists": {
+				in:  "CREATE TABLE u (id INT NOT NULL);\nALTER TABLE t RENAME TO u",
+				out: []string{"ds> error: table: unknown.u: invalid data"},
+			},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			e, err := mysql.Estimate(mysql.SetPrecision(0))
+			are.NoErr(err) // unexpected estimator error
+			var (
+				in  = "CREATE TABLE t (id INT NOT NULL);\n" + tt.in + ";\n"
+				buf = new(bytes.Buffer)
+			)
+			are.NoErr(e.Interact(strings.NewReader(in), buf)) // unexpected session error
+			res := buf.String()
+			for _, s := range tt.out {
+				are.True(strings.Contains(res, s+"\n")) // missing output
+			}
+		})
+	}
+}
+
+func TestEstimator_Interact_Errors(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in   string
+			out  string
+			last bool
+		}{
+			"Syntax": {
+				in:  "garbage();",
+				out: "ds> error: statement \"garbage()\": syntax error at position 8 near 'garbage': invalid data\n",
+			},
+			"FullText": {
+				in:  "ALTER TABLE t ADD c TEXT, ADD FULLTEXT KEY ();",
+				out: "ds> error: fulltext key column: missing data\n",
+			},
+			"EmptyFullText": {
+				in:  "CREATE TABLE u (c TEXT, FULLTEXT KEY ());",
+				out: "ds> error: fulltext key column: missing data\n",
+			},
+			"Unclosed": {
+				in:  "CREATE TABLE u (id INT, c VARCHAR(255);",
+				out: "ds> error: statement \"CREATE TABLE u (id INT, c VARCHAR(255)\": unclosed parenthesis: invalid data\n",
+			},
+			"Unterminated": {
+				in:   "ALTER TABLE t ADD c INT COMMENT 'a",
+				out:  "\nerror: statement \"ALTER TABLE t ADD c INT COMMENT 'a\": unterminated quoted string: invalid data\n",
+				last: true,
+			},
+			"Truncated": {
+				in:   "CREATE TABLE u (id INT",
+				out:  "\nerror: statement \"CREATE TABLE u (id INT\": unclosed parenthesis: invalid data\n",
+				last: true,
+			},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			e, err := mysql.Estimate(mysql.SetPrecision(0))
+			are.NoErr(err) // unexpected estimator error
+			in := "CREATE TABLE t (id INT NOT NULL);\n" + tt.in
+			if !tt.last {
+				// The statement in error is ignored, the session going on with the next one.
+				in += "\nALTER TABLE t ADD a INT NOT NULL;"
+			}
+			buf := new(bytes.Buffer)
+			are.NoErr(e.Interact(strings.NewReader(in), buf)) // unexpected session error
+			res := buf.String()
+			are.True(strings.Contains(res, tt.out)) // missing error
+			if tt.last {
+				// The last statement may not end with a semicolon.
+				are.True(strings.HasSuffix(res, tt.out)) // missing last error
+				return
+			}
+			are.True(strings.Contains(res, "table unknown.t changed: +4 B per row")) // missing next statement
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"strconv"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// DefaultVertices is the number of points by spatial value, used if not declared on the column.
+const DefaultVertices = 16
+
+// MySQL stores a spatial value with its SRID (4 bytes), followed by its well-known binary representation (WKB):
+// the byte order (1 byte) and the type of the geometry (4 bytes), then its points as pairs of doubles (16 bytes).
+// Any line string or ring is prefixed by its number of points, and any polygon or collection by its number of items,
+// each one using 4 bytes.
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-data-formats.html
+const (
+	sridSize   = 4
+	wkbHeader  = 1 + 4
+	wkbCount   = 4
+	pointSize  = 2 * 8
+	wkbPoint   = wkbHeader + pointSize
+	spatialMin = sridSize + wkbHeader + wkbCount
+)
+
+// spatial returns the size of the spatial data type with this number of points by value.
+// At least, the value is the smallest valid geometry of this type. At most, the points are spread
+// in as many items as possible, each one having the minimum number of points required.
+func (d DataType) spatial(vertices uint64) (min, max uint64) {
+	if vertices == 0 {
+		vertices = DefaultVertices
+	}
+	var (
+		// Overhead and minimum number of points of each item.
+		item   uint64
+		points uint64 = 1
+	)
+	switch d {
+	case Point:
+		return both(sridSize + wkbPoint)
+	case LineString:
+		points = 2
+	case Polygon:
+		// Only one ring.
+		item, points = wkbCount, 4
+	case GeometryCollection, Geometry:
+		// An empty collection is a valid geometry, and its largest value only contains points.
+		return spatialMin, ds.Add(spatialMin, ds.Mul(vertices, wkbPoint))
+	case MultiPoint:
+		item = wkbHeader
+	case MultiLineString:
+		item, points = wkbHeader+wkbCount, 2
+	case MultiPolygon:
+		item, points = wkbHeader+wkbCount+wkbCount, 4
+	default:
+		return 0, 0
+	}
+	if vertices < points {
+		vertices = points
+	}
+	return spatialMin + item + points*pointSize, ds.Add(spatialMin, ds.Mul(vertices/points, item), ds.Mul(vertices, pointSize))
+}
+
+// spatialAttributes extracts the SRID attribute of the column definition, not supported by the SQL parser.
+// See https://dev.mysql.com/doc/refman/8.0/en/spatial-type-overview.html
+func spatialAttributes(def tokens) (tokens, uint32, error) {
+	var (
+		id    uint64
+		err   error
+		start = def.next(-1) + 1
+		// The name of the column is kept as is.
+		res = append(tokens{}, def[:min(start, len(def))]...)
+	)
+	for pos := start; pos < len(def); pos++ {
+		switch t := def[pos]; {
+		case t.is(srid):
+			pos = def.next(pos)
+			id, err = strconv.ParseUint(def.at(pos).val, base10, bits32)
+			if err != nil {
+				return nil, 0, ds.WrapErr("column srid", ds.ErrInvalid)
+			}
+			continue
+		}
+		res = append(res, def[pos])
+	}
+	return res, uint32(id), nil
+}
+
+// Annotation used to describe the number of points by value of a spatial column.
+const annotationVertices = "vertices"
+
+// vertices returns the number of points by value declared in the column comment, if any.
+func vertices(comment string) (uint64, error) {
+	v, ok := annotations(comment)[annotationVertices]
+	if !ok {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(v, base10, bits64)
+	if err != nil {
+		return 0, ds.WrapErr("column vertices", ds.ErrInvalid)
+	}
+	return n, nil
+}
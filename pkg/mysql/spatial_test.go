@@ -0,0 +1,69 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestEstimator_Parse_Spatial(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in       string
+			opts     []mysql.Configurator
+			typ      mysql.DataType
+			srid     uint32
+			min, max uint64
+			err      error
+		}{
+			"Point":           {in: "g POINT NOT NULL", typ: mysql.Point, min: 25, max: 25},
+			"SRID":            {in: "g POINT NOT NULL SRID 4326", typ: mysql.Point, srid: 4326, min: 25, max: 25},
+			"LineString":      {in: "g LINESTRING NOT NULL", typ: mysql.LineString, min: 45, max: 269},
+			"Polygon":         {in: "g POLYGON NOT NULL", typ: mysql.Polygon, min: 81, max: 285},
+			"Geometry":        {in: "g GEOMETRY NOT NULL", typ: mysql.Geometry, min: 13, max: 349},
+			"MultiPoint":      {in: "g MULTIPOINT NOT NULL", typ: mysql.MultiPoint, min: 34, max: 349},
+			"MultiLineString": {in: "g MULTILINESTRING NOT NULL", typ: mysql.MultiLineString, min: 54, max: 341},
+			"MultiPolygon":    {in: "g MULTIPOLYGON NOT NULL", typ: mysql.MultiPolygon, min: 90, max: 321},
+			"Comment":         {in: "g LINESTRING NOT NULL COMMENT 'ds: vertices=100'", typ: mysql.LineString, min: 45, max: 1613},
+			"Minimum":         {in: "g LINESTRING NOT NULL COMMENT 'ds: vertices=1'", typ: mysql.LineString, min: 45, max: 45},
+			"Flag": {
+				in:   "g LINESTRING NOT NULL",
+				opts: []mysql.Configurator{mysql.SetVertices(4)},
+				typ:  mysql.LineString, min: 45, max: 77,
+			},
+			"Override": {
+				in:   "g LINESTRING NOT NULL COMMENT 'ds: vertices=100'",
+				opts: []mysql.Configurator{mysql.SetVertices(4)},
+				typ:  mysql.LineString, min: 45, max: 1613,
+			},
+			"Vertices":    {in: "g LINESTRING NOT NULL COMMENT 'ds: vertices=x'", err: ds.ErrInvalid},
+			"InvalidSRID": {in: "g POINT NOT NULL SRID x", err: ds.ErrInvalid},
+			"MissingSRID": {in: "g POINT NOT NULL SRID", err: ds.ErrInvalid},
+			"LargeSRID":   {in: "g POINT NOT NULL SRID 99999999999", err: ds.ErrInvalid},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dbs, err := parse("CREATE TABLE t ("+tt.in+")", tt.opts...)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			if tt.err != nil {
+				return
+			}
+			c := dbs[0].Tables[0].Columns[0]
+			are.Equal(tt.typ, c.DataType) // mismatch data type
+			are.Equal(tt.srid, c.SRID)    // mismatch srid
+			min, max := c.Size()
+			are.Equal(tt.min, min) // mismatch minimum size
+			are.Equal(tt.max, max) // mismatch maximum size
+		})
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package mysql provides methods to parse SQL and estimate MySQL data sizes.
+//
+// A schema is parsed from SQL statements with Parse, or built in Go with NewDatabase, NewTable
+// and their methods to add columns, indexes and tables. Each database, table, column or key
+// implements the ds.Data interface: its sizes are computed in-process with ds.Scale, ds.Expect
+// or ds.ExpectN. The Estimator applies the settings on a schema and renders its estimation.
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/xwb1989/sqlparser"
+)
+
+// Storage represents a storage. It can contain many MySQL database.
+type Storage []Database
+
+// addDatabase adds a database to the storage.
+func (s Storage) addDatabase(name, charset string) (Storage, string) {
+	_, err := s.get(name)
+	if err != nil {
+		return append(s, Database{
+			Name:    name,
+			Charset: charset,
+		}), name
+	}
+	return s, name
+}
+
+// dropDatabase drops a database by its name.
+func (s Storage) dropDatabase(name string) Storage {
+	i, err := s.get(name)
+	if err != nil {
+		return s
+	}
+	return append(s[:i], s[i+1:]...)
+}
+
+// createTable tries to create a table inside the given database, with this engine if none is declared.
+// An existing table with the same name is replaced, as after a DROP TABLE, unless ifNotExists is true.
+func (s Storage) createTable(dbName string, stmt *sqlparser.DDL, ext extension, eng Engine, ifNotExists bool) error {
+	i, err := s.get(dbName)
+	if err != nil {
+		return err
+	}
+	_, cur, err := s.table(dbName, stmt.NewName.Name.String())
+	if err == nil && ifNotExists {
+		return nil
+	}
+	if err != nil {
+		cur = notFound
+	}
+	// The charset of the table is the default one of its columns.
+	opts := options(stmt.TableSpec)
+	charset := Charset(opts[characterSet], s[i].Charset)
+	cols, diags, err := columns(stmt.TableSpec, charset)
+	if err != nil {
+		return err
+	}
+	if v, ok := opts[engine]; ok {
+		eng = ToEngine(v)
+	} else if eng == "" {
+		eng = InnoDB
+	}
+	t := Table{
+		Charset:     charset,
+		Columns:     cols,
+		Compression: ToCompression(opts[compression]),
+		Diagnostics: diags,
+		Engine:      eng,
+		Name:        stmt.NewName.Name.String(),
+		RowFormat:   ToRowFormat(opts[rowFormat]),
+	}
+	for p := range t.Diagnostics {
+		t.Diagnostics[p].Table = t.Name
+	}
+	if v, ok := opts[keyBlockSize]; ok {
+		t.KeyBlockSize, err = strconv.ParseUint(v, base10, bits64)
+		if err != nil {
+			return ds.WrapErr("table key block size", ds.ErrInvalid)
+		}
+	}
+	err = ext.apply(&t, charset)
+	if err != nil {
+		return err
+	}
+	t.diagnose()
+	err = t.addKeys(stmt.TableSpec, ext)
+	if err != nil {
+		return err
+	}
+	err = t.Analyze()
+	if err != nil {
+		return err
+	}
+	if cur != notFound {
+		s[i].Tables[cur] = t
+		return nil
+	}
+	s[i].Tables = append(s[i].Tables, t)
+	return nil
+}
+
+const (
+	characterSet = "charset"
+	compression  = "compression"
+	engine       = "engine"
+	keyBlockSize = "key_block_size"
+	rowFormat    = "row_format"
+
+	equal = "="
+	pair  = 2
+	space = " "
+)
+
+// options parses the table options (ex: engine=InnoDB default charset=latin1) to build kv options.
+// As with the ALTER TABLE statement, the CHARACTER SET option is named charset.
+func options(spec *sqlparser.TableSpec) map[string]string {
+	if spec == nil || spec.Options == "" {
+		return nil
+	}
+	var (
+		res = make(map[string]string)
+		ts  = lex(spec.Options)
+	)
+	for pos := ts.next(-1); pos < len(ts); pos = ts.next(pos) {
+		name := strings.ToLower(ts.at(pos).val)
+		switch name {
+		case "default", ",":
+			continue
+		case "character":
+			pos = ts.next(pos)
+			name = characterSet
+		}
+		pos = ts.optional(ts.next(pos), equal)
+		res[name] = ts.at(pos).text()
+	}
+	return res
+}
+
+// columns returns the columns of the table, with the diagnostics of the ones whose length can not be parsed.
+func columns(spec *sqlparser.TableSpec, dbCharset string) ([]Column, []Diagnostic, error) {
+	if spec == nil || len(spec.Columns) == 0 {
+		return nil, nil, nil
+	}
+	var (
+		diags []Diagnostic
+		err   error
+	)
+	res := make([]Column, len(spec.Columns))
+	for k, v := range spec.Columns {
+		c := Column{
+			Name:     v.Name.String(),
+			Charset:  Charset(v.Type.Charset, dbCharset),
+			DataType: ToDataType(v.Type.Type),
+			NotNull:  bool(v.Type.NotNull),
+		}
+		if v.Type.Length != nil {
+			c.DataSize, err = strconv.ParseUint(string(v.Type.Length.Val), base10, bits64)
+			if err != nil {
+				c.DataSize, c.Excluded = 0, true
+				diags = append(diags, Diagnostic{
+					Column: c.Name,
+					Err:    ds.WrapErr("data length "+string(v.Type.Length.Val), ds.ErrInvalid),
+				})
+			}
+		}
+		if v.Type.Comment != nil && c.DataType.IsSpatial() {
+			c.Vertices, err = vertices(string(v.Type.Comment.Val))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		res[k] = c
+	}
+	return res, diags, nil
+}
+
+// dropTable drops this database's table. It fails if the table does not exist, unless ifExists is true.
+func (s Storage) dropTable(dbName, name string, ifExists bool) error {
+	i, p, err := s.table(dbName, name)
+	if err != nil {
+		if ifExists {
+			return nil
+		}
+		return err
+	}
+	s[i].Tables = append(s[i].Tables[:p], s[i].Tables[p+1:]...)
+	return nil
+}
+
+// renameTable renames this database's table, moving it to the other database if needed.
+func (s Storage) renameTable(dbName, name, toDBName, to string) error {
+	i, p, err := s.table(dbName, name)
+	if err != nil {
+		return err
+	}
+	j, err := s.get(toDBName)
+	if err != nil {
+		return err
+	}
+	if _, _, err = s.table(toDBName, to); err == nil {
+		return fmt.Errorf("table: %s.%s: %w", toDBName, to, ds.ErrInvalid)
+	}
+	t := s[i].Tables[p].clone()
+	t.Name = to
+	for k := range t.Diagnostics {
+		t.Diagnostics[k].Table = to
+	}
+	if i == j {
+		s[i].Tables[p] = t
+		return nil
+	}
+	s[i].Tables = append(s[i].Tables[:p], s[i].Tables[p+1:]...)
+	s[j].Tables = append(s[j].Tables, t)
+	return nil
+}
+
+// table returns the positions of the database and of its table.
+func (s Storage) table(dbName, name string) (db, pos int, err error) {
+	db, err = s.get(dbName)
+	if err != nil {
+		return
+	}
+	for p, t := range s[db].Tables {
+		if t.Name == name {
+			return db, p, nil
+		}
+	}
+	return db, 0, fmt.Errorf("table: %s.%s: %w", dbName, name, ds.ErrInvalid)
+}
+
+// clone returns a copy of the storage, to change it without changing the original one.
+func (s Storage) clone() Storage {
+	res := make(Storage, len(s))
+	for i, d := range s {
+		tables := make([]Table, len(d.Tables))
+		for p, t := range d.Tables {
+			tables[p] = t.clone()
+		}
+		d.Tables = tables
+		res[i] = d
+	}
+	return res
+}
+
+func (s Storage) get(name string) (pos int, err error) {
+	for i, d := range s {
+		if d.Name == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("database: %s: %w", name, ds.ErrInvalid)
+}
@@ -0,0 +1,350 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/xwb1989/sqlparser"
+)
+
+// Table represents a table.
+type Table struct {
+	Name   string
+	Engine Engine
+	// Charset is the default charset of the columns.
+	Charset   string
+	Columns   []Column
+	Indexes   []Index
+	RowFormat RowFormat
+	// KeyBlockSize is the size in KB of the compressed pages.
+	KeyBlockSize uint64
+	// Compression is the algorithm used by the transparent page compression.
+	Compression Compression
+	// PageSize is the InnoDB page size in bytes (innodb_page_size). If zero, DefaultPageSize is used.
+	PageSize uint64
+	// CompressionRatio forces the ratio used to estimate the compressed sizes.
+	// If zero, the ratio is assumed based on the columns data types.
+	CompressionRatio float64
+	Partitioning     Partitioning
+	// Diagnostics lists the issues found on the columns, excluded from the estimation.
+	Diagnostics []Diagnostic
+}
+
+// Analyze rechallenges any table properties to validate them, to define the primary key or the row format.
+func (t *Table) Analyze() error {
+	switch {
+	case t.Name == "":
+		return ds.WrapErr("table name", ds.ErrMissing)
+	case t.Engine == "":
+		return ds.WrapErr("table engine", ds.ErrInvalid)
+	case len(t.Columns) == 0:
+		return ds.WrapErr("table column", ds.ErrMissing)
+	case t.KeyBlockSize > 0 && !validKeyBlockSize(t.KeyBlockSize):
+		return ds.WrapErr("table key block size", ds.ErrInvalid)
+	case t.CompressionRatio < 0:
+		return ds.WrapErr("table compression ratio", ds.ErrInvalid)
+	case t.PageSize > 0 && !ValidPageSize(t.PageSize):
+		return ds.WrapErr("table page size", ds.ErrInvalid)
+	default:
+		if t.Engine == InnoDB && t.KeyBlockSize > 0 && t.RowFormat == UnknownRowFormat {
+			// Specifying a KEY_BLOCK_SIZE implies the compressed row format.
+			t.RowFormat = CompressedRowFormat
+		}
+		t.RowFormat = t.Engine.RowFormat(t.Columns, t.RowFormat)
+		if t.compressed() && t.KeyBlockSize == 0 {
+			t.KeyBlockSize = DefaultKeyBlockSize
+		}
+		return nil
+	}
+}
+
+const table = "table"
+
+// Kind implements the ds.Data interface.
+func (t Table) Kind() string {
+	var a []string
+	if s := t.Engine.String(); s != "" {
+		a = append(a, s)
+	}
+	if s := t.RowFormat.String(); s != "" {
+		a = append(a, s)
+	}
+	if t.compressed() {
+		a = append(a, fmt.Sprintf("%dK", t.KeyBlockSize))
+	}
+	if t.Compression.Enabled() {
+		a = append(a, t.Compression.String())
+	}
+	if t.partitioned() {
+		a = append(a, fmt.Sprintf("%s(%d)", t.Partitioning.Method, len(t.Partitioning.Partitions)))
+	}
+	return fmt.Sprintf("%s(%s)", table, strings.Join(a, ", "))
+}
+
+// Fields returns columns properties.
+func (t Table) Fields() []ds.Data {
+	return t.Engine.Fields(t.Columns, t.RowFormat)
+}
+
+// Keys returns keys properties.
+func (t Table) Keys() []ds.Data {
+	return t.Engine.Keys(t.Indexes, t.primaryKeyIndex())
+}
+
+// Children implements the ds.Node interface.
+// It returns the columns, the keys and the partitions of the table.
+func (t Table) Children() []ds.Data {
+	res := append(t.Fields(), t.Keys()...)
+	return append(res, t.Partitions()...)
+}
+
+// Details implements the ds.Detailer interface.
+// It returns the footprints of the table.
+func (t Table) Details() []ds.Data {
+	return t.Footprints()
+}
+
+func (t *Table) primaryKeyIndex() int {
+	for p, k := range t.Indexes {
+		if k.Primary {
+			return p
+		}
+	}
+	return notFound
+}
+
+// Size implements the ds.Data interface.
+// With the compressed row format, both data and keys are compressed.
+func (t Table) Size() (min, max uint64) {
+	min, max = t.rawSize()
+	if t.compressed() {
+		return t.compress(min), t.compress(max)
+	}
+	return
+}
+
+// Distribution implements the ds.Distributor interface.
+// The sizes of the columns and of the keys are independent, the ones of the columns being fitted
+// to the row format of the engine, then the whole to the compression if any.
+func (t Table) Distribution() ds.Distribution {
+	res := t.rowDistribution()
+	for _, k := range t.Keys() {
+		res = res.Add(ds.Expect(k))
+	}
+	n, x := t.rawSize()
+	a, b := t.Size()
+	return res.Fit(n, x, a, b)
+}
+
+// rowDistribution returns the distribution of the size of the columns, fitted to the row format of the engine.
+func (t Table) rowDistribution() ds.Distribution {
+	var (
+		res        ds.Distribution
+		n, x, a, b uint64
+	)
+	for _, c := range t.Engine.nulls(stored(t.Columns), t.RowFormat) {
+		res = res.Add(ds.Expect(c))
+		a, b = c.Size()
+		n, x = ds.Add(n, a), ds.Add(x, b)
+	}
+	a, b = t.Engine.RowSize(t.Columns, t.RowFormat)
+	return res.Fit(n, x, a, b)
+}
+
+// Groups implements the ds.Grouper interface.
+// It splits the table into the data of its rows, composed of the columns, and its indexes, composed of the keys.
+// Both are sized before compression.
+func (t Table) Groups() []ds.Node {
+	return t.groups(t.Fields(), t.Keys())
+}
+
+func (t Table) groups(fields, keys []ds.Data) []ds.Node {
+	var (
+		rows  = part{name: dataPart, dist: t.rowDistribution()}
+		index = part{name: indexPart}
+	)
+	rows.min, rows.max = t.Engine.RowSize(t.Columns, t.RowFormat)
+	index.min, index.max = ds.Sum(keys...)
+	for _, k := range keys {
+		index.dist = index.dist.Add(ds.Expect(k))
+	}
+	return []ds.Node{ds.NewGroup(rows, fields...), ds.NewGroup(index, keys...)}
+}
+
+// Names of the parts of a table.
+const (
+	dataPart  = "data"
+	indexPart = "index"
+)
+
+// part is a part of a table, like its data or its indexes.
+type part struct {
+	name     string
+	min, max uint64
+	dist     ds.Distribution
+}
+
+// Size implements the ds.Data interface.
+func (p part) Size() (min, max uint64) {
+	return p.min, p.max
+}
+
+// Distribution implements the ds.Distributor interface.
+func (p part) Distribution() ds.Distribution {
+	return p.dist
+}
+
+// Kind implements the ds.Data interface.
+func (p part) Kind() string {
+	return ""
+}
+
+// String implements the ds.Data interface.
+func (p part) String() string {
+	return p.name
+}
+
+func (t Table) rawSize() (min, max uint64) {
+	min, max = t.Engine.RowSize(t.Columns, t.RowFormat)
+	var n, x uint64
+	n, x = ds.Sum(t.Keys()...)
+	return ds.Add(min, n), ds.Add(max, x)
+}
+
+// String implements the ds.Data interface.
+func (t Table) String() string {
+	return t.Name
+}
+
+func (t *Table) addKeys(spec *sqlparser.TableSpec, ext extension) (err error) {
+	if spec == nil {
+		return
+	}
+	for _, c := range t.Columns {
+		v := ext.columns[c.Name]
+		if !v.primary && !v.unique {
+			continue
+		}
+		// A key declared in the column definition is named as the column, except the primary one.
+		name := c.Name
+		if v.primary {
+			name = primaryKeyName
+		}
+		err = t.addKey(Index{
+			Name:    name,
+			Parts:   []KeyPart{{Column: Column{Name: c.Name}}},
+			Primary: v.primary,
+		})
+		if err != nil {
+			return
+		}
+	}
+	for i, k := range spec.Indexes {
+		var (
+			name  string
+			parts = make([]KeyPart, len(k.Columns))
+		)
+		for p, c := range k.Columns {
+			parts[p].Name = c.Column.String()
+			parts[p].Desc = ext.desc(i, p)
+			if c.Length != nil {
+				parts[p].Length, err = strconv.ParseUint(string(c.Length.Val), base10, bits64)
+				if err != nil {
+					return ds.WrapErr("key part length", ds.ErrInvalid)
+				}
+			}
+		}
+		if k.Info != nil {
+			name = k.Info.Name.String()
+		}
+		err = t.addKey(Index{
+			Name:    name,
+			Parts:   parts,
+			Primary: primary(k.Info),
+			Type:    indexType(k.Info),
+		})
+		if err != nil {
+			return
+		}
+	}
+	for _, ft := range ext.fullText {
+		err = t.addFullTextKey(ft)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func primary(info *sqlparser.IndexInfo) bool {
+	if info == nil {
+		return false
+	}
+	return info.Primary
+}
+
+func indexType(info *sqlparser.IndexInfo) IndexType {
+	if info != nil && info.Spatial {
+		return SpatialIndex
+	}
+	return BTreeIndex
+}
+
+func (t *Table) addKey(k Index) error {
+	names := make([]string, len(k.Parts))
+	for p, v := range k.Parts {
+		names[p] = v.Name
+	}
+	cols := t.columnsNamed(names)
+	if len(cols) == 0 {
+		return ds.WrapErr("key column", ds.ErrInvalid)
+	}
+	for p, c := range cols {
+		if k.Primary {
+			// As with MySQL, the columns of the primary key are implicitly NOT NULL.
+			c.NotNull = true
+			t.Columns[t.columnIndex(c.Name)].NotNull = true
+		}
+		k.Parts[p].Column = c
+		if err := k.Parts[p].valid(k.Type); err != nil {
+			return err
+		}
+	}
+	t.Indexes = append(t.Indexes, k)
+	return nil
+}
+
+// notFound is the index value returned if the data is not found.
+const notFound = -1
+
+func (t *Table) columnIndex(name string) int {
+	for i, c := range t.Columns {
+		if c.Name == name {
+			return i
+		}
+	}
+	return notFound
+}
+
+func (t *Table) columnsNamed(names []string) []Column {
+	if len(names) == 0 {
+		return nil
+	}
+	var res []Column
+	for _, name := range names {
+		i := t.columnIndex(name)
+		if i != notFound {
+			res = append(res, t.Columns[i])
+		}
+	}
+	if len(res) != len(names) {
+		return nil
+	}
+	return res
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Watch estimates the SQL files, then watches them to estimate them again on each change, until the context is done.
+// The paths are files or directories, whose SQL files are read in lexical order, as if they were a single file.
+// After each new estimation, its report is rendered, followed by the size delta of the tables and the databases
+// changed since the previous one. The files are polled at the watch interval, and a change is only estimated
+// once they are unchanged during an interval, to wait for the end of successive writes.
+// A failed estimation is reported without ending the watch.
+func (e *Estimator) Watch(ctx context.Context, w io.Writer, paths ...string) error {
+	if len(paths) == 0 {
+		return ds.WrapErr("watched paths", ds.ErrMissing)
+	}
+	files, err := watch(paths)
+	if err != nil {
+		return err
+	}
+	var dbs Storage
+	for {
+		dbs, err = e.rerun(w, files, dbs)
+		if err != nil {
+			return err
+		}
+		files, err = e.wait(ctx, paths, files)
+		if err != nil {
+			// The context is done.
+			return nil
+		}
+	}
+}
+
+// DefaultWatchInterval is the default interval between two polls of the watched files.
+const DefaultWatchInterval = 500 * time.Millisecond
+
+// rerun estimates the files and renders the report, followed by the delta since the previous storage, if any.
+// It returns the new storage, or the previous one if the estimation fails.
+func (e *Estimator) rerun(w io.Writer, files snapshot, prev Storage) (dbs Storage, err error) {
+	r, err := files.read()
+	if err == nil {
+		dbs, err = e.Parse(r)
+	}
+	if err != nil {
+		_, err = fmt.Fprintf(w, "error: %s\n", err)
+		return prev, err
+	}
+	err = e.Render(w, dbs)
+	if err != nil || prev == nil {
+		return dbs, err
+	}
+	return dbs, e.printChanges(w, e.filter(prev), e.filter(dbs))
+}
+
+// wait polls the files until they change, then until they are unchanged during an interval.
+// It only fails when the context is done.
+func (e *Estimator) wait(ctx context.Context, paths []string, last snapshot) (snapshot, error) {
+	t := time.NewTicker(e.watchInterval)
+	defer t.Stop()
+	pending := last
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+		cur, err := watch(paths)
+		if err != nil {
+			// The file may be replaced, like by an editor saving it.
+			pending = nil
+			continue
+		}
+		if !cur.equal(pending) {
+			pending = cur
+			continue
+		}
+		if !cur.equal(last) {
+			return cur, nil
+		}
+	}
+}
+
+// printChanges prints the size delta of the tables and of the databases changed since the previous storage.
+func (e *Estimator) printChanges(w io.Writer, prev, dbs Storage) error {
+	var changes int
+	for _, name := range databaseNames(prev, dbs) {
+		for _, tb := range tableNames(prev, dbs, name) {
+			before, after := prev.tableData(name, tb), dbs.tableData(name, tb)
+			if e.unchanged(before, after) {
+				continue
+			}
+			changes++
+			err := e.printDelta(w, table, name+"."+tb, "", before, after)
+			if err != nil {
+				return err
+			}
+		}
+		before, after := prev.databaseData(name), dbs.databaseData(name)
+		if e.unchanged(before, after) {
+			continue
+		}
+		changes++
+		err := e.printDelta(w, db, name, "", before, after)
+		if err != nil {
+			return err
+		}
+	}
+	if changes > 0 {
+		return nil
+	}
+	_, err := fmt.Fprintln(w, "no size change")
+	return err
+}
+
+// unchanged returns true if both data exist with the same sizes, per row and for the number of rows.
+func (e *Estimator) unchanged(before, after ds.Data) bool {
+	return before != nil && after != nil && newSizes(before, e.perN) == newSizes(after, e.perN)
+}
+
+// filter returns the databases and the tables to report, ignoring the top N of each database.
+func (e *Estimator) filter(dbs Storage) Storage {
+	res := make(Storage, 0, len(dbs))
+	for _, d := range dbs {
+		if !match(e.databasePattern, d.Name) {
+			continue
+		}
+		tables := make([]Table, 0, len(d.Tables))
+		for _, t := range d.Tables {
+			if match(e.tablePattern, t.Name) && (e.engine == "" || t.Engine == e.engine) {
+				tables = append(tables, t)
+			}
+		}
+		d.Tables = tables
+		res = append(res, d)
+	}
+	return res
+}
+
+// databaseNames returns the names of the databases of the new storage, followed by the dropped ones.
+func databaseNames(prev, dbs Storage) []string {
+	var res []string
+	for _, s := range []Storage{dbs, prev} {
+		for _, d := range s {
+			if !contains(res, d.Name) {
+				res = append(res, d.Name)
+			}
+		}
+	}
+	return res
+}
+
+// tableNames returns the names of the tables of the database in the new storage, followed by the dropped ones.
+func tableNames(prev, dbs Storage, name string) []string {
+	var res []string
+	for _, s := range []Storage{dbs, prev} {
+		i, err := s.get(name)
+		if err != nil {
+			continue
+		}
+		for _, t := range s[i].Tables {
+			if !contains(res, t.Name) {
+				res = append(res, t.Name)
+			}
+		}
+	}
+	return res
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshot lists the watched files with their size and modification time.
+type snapshot []file
+
+type file struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+const sqlExt = ".sql"
+
+// watch returns the snapshot of the files, those of the directories being their SQL files in lexical order.
+func watch(paths []string) (snapshot, error) {
+	res := make(snapshot, 0, len(paths))
+	for _, path := range paths {
+		err := filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || (name != path && !strings.EqualFold(filepath.Ext(name), sqlExt)) {
+				return nil
+			}
+			res = append(res, file{path: name, size: info.Size(), modTime: info.ModTime()})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// equal returns true if both snapshots list the same files, unchanged.
+func (s snapshot) equal(o snapshot) bool {
+	if (s == nil) != (o == nil) || len(s) != len(o) {
+		return false
+	}
+	for i := range s {
+		if s[i].path != o[i].path || s[i].size != o[i].size || !s[i].modTime.Equal(o[i].modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// read returns the content of the files, one after the other.
+func (s snapshot) read() (io.Reader, error) {
+	var buf strings.Builder
+	for _, f := range s {
+		b, err := ioutil.ReadFile(f.path)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+		// The last statement of a file may not end with a semicolon.
+		buf.WriteString("\n;\n")
+	}
+	return strings.NewReader(buf.String()), nil
+}
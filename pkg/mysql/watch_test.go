@@ -0,0 +1,151 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mysql_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+func TestEstimator_Watch(t *testing.T) {
+	are := is.New(t)
+	dir, err := ioutil.TempDir("", "ds")
+	are.NoErr(err) // unexpected temporary directory error
+	defer func() { _ = os.RemoveAll(dir) }()
+	var (
+		a = filepath.Join(dir, "a.sql")
+		b = filepath.Join(dir, "b.sql")
+	)
+	are.NoErr(ioutil.WriteFile(a, []byte("CREATE DATABASE shop;\nCREATE TABLE t (id INT NOT NULL)"), 0o600)) // write
+	are.NoErr(ioutil.WriteFile(filepath.Join(dir, "README"), []byte("not SQL"), 0o600))                      // write
+
+	var (
+		buf         = new(syncBuffer)
+		done        = make(chan error, 1)
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+	e, err := mysql.Estimate(
+		mysql.SetPrecision(0),
+		mysql.SetWatchInterval(10*time.Millisecond),
+		mysql.SetDiagnosticOutput(buf),
+	)
+	are.NoErr(err)                                                                    // unexpected estimator error
+	are.True(errors.Is(e.Watch(context.Background(), ioutil.Discard), ds.ErrMissing)) // expected missing paths
+	are.True(e.Watch(context.Background(), ioutil.Discard, "/not/found") != nil)      // expected missing path
+
+	defer cancel()
+	go func() {
+		done <- e.Watch(ctx, buf, dir)
+	}()
+	are.True(buf.waitFor("| shop ")) // missing first report
+
+	are.NoErr(ioutil.WriteFile(b, []byte("USE shop;\nCREATE TABLE u (id BIGINT NOT NULL);"), 0o600)) // write
+	out := "table shop.u created: +8 B per row (8 B - 8 B), +800 B for 100 rows (800 B - 800 B)\n" +
+		"database shop changed: +8 B per row (12 B - 12 B), +800 B for 100 rows (1 KB - 1 KB)\n"
+	are.True(buf.waitFor(out)) // missing delta
+
+	are.NoErr(ioutil.WriteFile(b, []byte("DROP TABLE x;"), 0o600)) // write
+	are.True(buf.waitFor("error: table: shop.x: invalid data\n"))  // missing error
+
+	are.NoErr(ioutil.WriteFile(b, []byte("garbage()"), 0o600))                                   // write
+	are.True(buf.waitFor("warning: statement \"garbage()\": syntax error"))                      // missing syntax error
+	are.True(buf.waitFor("table shop.u dropped: -8 B per row (0 B - 0 B), -800 B for 100 rows")) // missing drop
+
+	are.NoErr(os.Remove(b))                                                                // remove
+	are.NoErr(ioutil.WriteFile(b, []byte("CREATE TABLE shop.v (id INT NOT NULL)"), 0o600)) // write
+	are.True(buf.waitFor("table shop.v created"))                                          // missing creation
+	cancel()
+	are.NoErr(<-done) // unexpected watch error
+}
+
+func TestEstimator_Watch_Truncated(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			strict bool
+			out    string
+		}{
+			"Default": {out: "warning: statement \"CREATE TABLE shop.u (id INT\": unclosed parenthesis: invalid data (skipped)\n"},
+			"Strict":  {strict: true, out: "error: statement \"CREATE TABLE shop.u (id INT\": unclosed parenthesis: invalid data\n"},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "ds")
+			are.NoErr(err) // unexpected temporary directory error
+			defer func() { _ = os.RemoveAll(dir) }()
+			var (
+				a           = filepath.Join(dir, "a.sql")
+				b           = filepath.Join(dir, "b.sql")
+				buf         = new(syncBuffer)
+				done        = make(chan error, 1)
+				ctx, cancel = context.WithCancel(context.Background())
+			)
+			defer cancel()
+			are.NoErr(ioutil.WriteFile(a, []byte("CREATE DATABASE shop;\nCREATE TABLE t (id INT NOT NULL)"), 0o600)) // write
+			e, err := mysql.Estimate(
+				mysql.SetStrictMode(tt.strict),
+				mysql.SetWatchInterval(10*time.Millisecond),
+				mysql.SetDiagnosticOutput(buf),
+			)
+			are.NoErr(err) // unexpected estimator error
+			go func() {
+				done <- e.Watch(ctx, buf, dir)
+			}()
+			are.True(buf.waitFor("| shop ")) // missing first report
+
+			// A file being written is first truncated.
+			are.NoErr(ioutil.WriteFile(b, []byte("CREATE TABLE shop.u (id INT"), 0o600)) // write
+			are.True(buf.waitFor(tt.out))                                                // missing syntax error
+
+			are.NoErr(ioutil.WriteFile(b, []byte("CREATE TABLE shop.u (id INT NOT NULL)"), 0o600)) // write
+			are.True(buf.waitFor("table shop.u created"))                                          // missing creation
+			cancel()
+			are.NoErr(<-done) // unexpected watch error
+		})
+	}
+}
+
+// syncBuffer is a buffer safe for concurrent use, to read the output of the watch while it is written.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor returns true once the output contains the string, false after a second.
+func (b *syncBuffer) waitFor(s string) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		if strings.Contains(b.String(), s) {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
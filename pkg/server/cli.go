@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/rvflash/ds/pkg/ds"
+)
+
+// Config lists the settings of the server.
+// The yaml tags are the keys of the settings in the configuration file, see ds.ConfigFile.
+type Config struct {
+	// Addr is the TCP address to listen on, like localhost:8080.
+	Addr string `yaml:"addr"`
+	// MaxBodySize is the maximum size of the body of a request, like 1MiB.
+	MaxBodySize ds.Unit `yaml:"max_body_size"`
+	// Timeout is the maximum duration of an estimation, like 10s.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// NewCLI returns the server as a subcommand.
+func NewCLI() *CLI {
+	return new(CLI)
+}
+
+// CLI is the server as a subcommand. It implements the ds.Estimator and ds.Configurable interfaces.
+type CLI struct {
+	Config
+}
+
+// Name implements the ds.Estimator interface.
+func (c *CLI) Name() string {
+	return Command
+}
+
+// Usage implements the ds.Estimator interface.
+func (c *CLI) Usage() string {
+	return "serves an HTTP API to estimate the data sizes of the SQL statements posted to /mysql"
+}
+
+// SetFlags implements the ds.Estimator interface.
+func (c *CLI) SetFlags(fs *flag.FlagSet) {
+	s := "TCP address to listen on"
+	fs.StringVar(&c.Addr, "addr", DefaultAddr, s)
+	s = "maximum size of the body of a request, in bytes or with a unit, like 1MiB"
+	c.MaxBodySize = DefaultMaxBodySize
+	fs.Var(&c.MaxBodySize, "max-body-size", s)
+	s = "maximum duration of an estimation"
+	fs.DurationVar(&c.Timeout, "timeout", DefaultTimeout, s)
+}
+
+// Settings implements the ds.Configurable interface.
+func (c *CLI) Settings() interface{} {
+	return &c.Config
+}
+
+// Run implements the ds.Estimator interface.
+// It serves the API until an interrupt signal, then shuts down the server gracefully.
+// The reader is not used.
+func (c *CLI) Run(_ io.Reader, w io.Writer) error {
+	h, err := New(SetMaxBodySize(uint64(c.MaxBodySize)), SetTimeout(c.Timeout))
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Addr:              c.Addr,
+		Handler:           h,
+		ReadHeaderTimeout: c.Timeout,
+		ReadTimeout:       c.Timeout,
+		WriteTimeout:      2 * c.Timeout,
+		IdleTimeout:       idleTimeout,
+	}
+	done := make(chan error, 1)
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt)
+		<-quit
+		signal.Stop(quit)
+		ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+		defer cancel()
+		done <- srv.Shutdown(ctx)
+	}()
+	_, err = fmt.Fprintf(w, "listening on %s\n", c.Addr)
+	if err != nil {
+		return err
+	}
+	err = srv.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return <-done
+}
+
+const idleTimeout = time.Minute
@@ -0,0 +1,238 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package server provides an HTTP API to estimate data sizes.
+//
+// The SQL statements to estimate are posted to the /mysql endpoint, as the body of the request
+// with the options in its query string, like /mysql?rows=1e6,1e9&format=json, or as the sql field of a form
+// with the options as other fields. The options are named as the keys of the configuration file, see mysql.Config,
+// except the paths of the profiles and of the template, not available.
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/ds/render"
+	"github.com/rvflash/ds/pkg/mysql"
+)
+
+// Default values used to configure the server.
+const (
+	Command            = "serve"
+	DefaultAddr        = "localhost:8080"
+	DefaultMaxBodySize = ds.MebiByte
+	DefaultTimeout     = 10 * time.Second
+)
+
+// Configurator is implemented by any method exposing cursor to adjust the handler.
+type Configurator func(*Handler) error
+
+// SetMaxBodySize defines the maximum size in bytes of the body of a request.
+func SetMaxBodySize(size uint64) Configurator {
+	return func(h *Handler) error {
+		if size == 0 || size > uint64(maxInt64) {
+			return ds.WrapErr("max body size", ds.ErrInvalid)
+		}
+		h.maxBodySize = int64(size)
+		return nil
+	}
+}
+
+const maxInt64 = 1<<63 - 1
+
+// SetTimeout defines the maximum duration of an estimation.
+// Once elapsed, the timeout response is sent, and the estimation stops before its next SQL statement.
+func SetTimeout(d time.Duration) Configurator {
+	return func(h *Handler) error {
+		if d <= 0 {
+			return ds.WrapErr("timeout", ds.ErrInvalid)
+		}
+		h.timeout = d
+		return nil
+	}
+}
+
+// New returns the HTTP handler of the API.
+func New(opts ...Configurator) (*Handler, error) {
+	opts = append([]Configurator{
+		SetMaxBodySize(uint64(DefaultMaxBodySize)),
+		SetTimeout(DefaultTimeout),
+	}, opts...)
+	h := new(Handler)
+	for _, opt := range opts {
+		err := opt(h)
+		if err != nil {
+			return nil, err
+		}
+	}
+	h.mux = http.NewServeMux()
+	h.mux.Handle("/"+mysql.Command, http.TimeoutHandler(http.HandlerFunc(h.mysql), h.timeout, "estimation timeout\n"))
+	return h, nil
+}
+
+// Handler is the HTTP handler of the API. It is safe for concurrent use, each request having its own estimator.
+type Handler struct {
+	mux         *http.ServeMux
+	maxBodySize int64
+	timeout     time.Duration
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// Names of the request fields.
+const (
+	sqlField      = "sql"
+	profilesField = "profiles"
+	templateField = "template"
+)
+
+// DiagnosticHeader is the header of the response listing the diagnostics of the estimation, if any.
+const DiagnosticHeader = "Ds-Diagnostic"
+
+func (h *Handler) mysql(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+	sql, err := statements(r)
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+	var (
+		diags = new(bytes.Buffer)
+		cli   = mysql.NewCLI(diags)
+		keys  = map[string]struct{}{sqlField: {}}
+	)
+	cli.Config = mysql.DefaultConfig()
+	err = ds.Configure(&cli.Config, func(key string) (string, bool) {
+		if key == profilesField || key == templateField {
+			// No access to the files of the server.
+			return "", false
+		}
+		keys[key] = struct{}{}
+		if _, ok := r.Form[key]; !ok {
+			return "", false
+		}
+		return r.Form.Get(key), true
+	})
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+	for key := range r.Form {
+		if _, ok := keys[key]; !ok {
+			h.fail(w, ds.WrapErr("option "+key, ds.ErrInvalid))
+			return
+		}
+	}
+	buf := new(bytes.Buffer)
+	// The context of the request is done on timeout, to stop the estimation.
+	err = cli.RunContext(r.Context(), strings.NewReader(sql), buf)
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+	if r.Context().Err() != nil {
+		// Timeout already sent.
+		return
+	}
+	for _, d := range strings.Split(strings.TrimSpace(diags.String()), "\n") {
+		if d != "" {
+			w.Header().Add(DiagnosticHeader, d)
+		}
+	}
+	w.Header().Set("Content-Type", contentType(cli.Config))
+	_, _ = w.Write(buf.Bytes())
+}
+
+// statements returns the SQL statements of the request: the sql field of a form, or the body.
+// The form values and the query string are parsed as options.
+func statements(r *http.Request) (string, error) {
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if ct == "multipart/form-data" {
+		err := r.ParseMultipartForm(0)
+		if err != nil {
+			return "", badRequest(err)
+		}
+		return r.PostForm.Get(sqlField), nil
+	}
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	r.Form, err = url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		return "", badRequest(err)
+	}
+	if ct != "application/x-www-form-urlencoded" {
+		return string(b), nil
+	}
+	// Without sql field, the body is the statements, as posted by curl --data-binary.
+	vs, err := url.ParseQuery(string(b))
+	if err != nil || vs.Get(sqlField) == "" {
+		return string(b), nil
+	}
+	for k, v := range r.Form {
+		vs[k] = append(vs[k], v...)
+	}
+	r.Form = vs
+	return vs.Get(sqlField), nil
+}
+
+func badRequest(err error) error {
+	return ds.WrapErr(err.Error(), ds.ErrInvalid)
+}
+
+// contentType returns the media type of the output format.
+func contentType(c mysql.Config) string {
+	if c.Batch {
+		return "text/csv; charset=utf-8"
+	}
+	f, _ := render.ToFormat(c.Format)
+	switch f {
+	case render.CSVFormat:
+		return "text/csv; charset=utf-8"
+	case render.JSONFormat:
+		return "application/json"
+	case render.MarkdownFormat:
+		return "text/markdown; charset=utf-8"
+	case render.HTMLFormat:
+		return "text/html; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// fail replies with the status code matching the error.
+func (h *Handler) fail(w http.ResponseWriter, err error) {
+	var (
+		code    = http.StatusInternalServerError
+		tooMuch = "http: request body too large"
+	)
+	switch {
+	// The error of the body reader has no type.
+	case strings.Contains(err.Error(), tooMuch):
+		code = http.StatusRequestEntityTooLarge
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return
+	case errors.Is(err, ds.ErrInvalid), errors.Is(err, ds.ErrMissing):
+		code = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), code)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package server_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/ds/pkg/ds"
+	"github.com/rvflash/ds/pkg/server"
+)
+
+const schema = `
+CREATE DATABASE shop;
+CREATE TABLE small (id TINYINT NOT NULL) ENGINE=MyISAM;
+`
+
+func TestNew(t *testing.T) {
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			opt server.Configurator
+			err error
+		}{
+			"Body":    {opt: server.SetMaxBodySize(0), err: ds.ErrInvalid},
+			"Timeout": {opt: server.SetTimeout(0), err: ds.ErrInvalid},
+			"OK":      {opt: server.SetTimeout(time.Second)},
+		}
+	)
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			_, err := server.New(tt.opt)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+		})
+	}
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	var (
+		are  = is.New(t)
+		form = url.Values{"sql": {schema}, "format": {"csv"}}.Encode()
+		dt   = map[string]struct {
+			method,
+			target,
+			contentType,
+			body string
+			code int
+			ct,
+			out string
+		}{
+			"Method": {method: http.MethodGet, target: "/mysql", code: http.StatusMethodNotAllowed},
+			"Path":   {method: http.MethodPost, target: "/", body: schema, code: http.StatusNotFound},
+			"Size":   {method: http.MethodPost, target: "/mysql", body: schema + strings.Repeat(" ", 1024), code: http.StatusRequestEntityTooLarge},
+			"Empty":  {method: http.MethodPost, target: "/mysql", code: http.StatusBadRequest},
+			"Option": {method: http.MethodPost, target: "/mysql?format=xml", body: schema, code: http.StatusBadRequest},
+			"Unknown": {
+				method: http.MethodPost, target: "/mysql?color=red", body: schema, code: http.StatusBadRequest,
+			},
+			"File": {
+				method: http.MethodPost, target: "/mysql?template=/etc/passwd", body: schema, code: http.StatusBadRequest,
+			},
+			"Body": {
+				method: http.MethodPost, target: "/mysql?rows=1e3", body: schema,
+				code: http.StatusOK, ct: "text/plain; charset=utf-8", out: "X 1000 (MIN)",
+			},
+			"Raw": {
+				method: http.MethodPost, target: "/mysql?format=csv", contentType: "application/x-www-form-urlencoded", body: schema,
+				code: http.StatusOK, ct: "text/csv; charset=utf-8", out: "small,",
+			},
+			"Query": {method: http.MethodPost, target: "/mysql?a=%zz", body: schema, code: http.StatusBadRequest},
+			"Form": {
+				method: http.MethodPost, target: "/mysql", contentType: "application/x-www-form-urlencoded", body: form,
+				code: http.StatusOK, ct: "text/csv; charset=utf-8", out: "Data,Type,",
+			},
+		}
+	)
+	h, err := server.New(server.SetMaxBodySize(1024))
+	are.NoErr(err) // unexpected handler error
+	for name, tt := range dt {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			are.Equal(tt.code, w.Code) // mismatch status code
+			if tt.code != http.StatusOK {
+				return
+			}
+			are.Equal(tt.ct, w.Header().Get("Content-Type"))    // mismatch content type
+			are.True(strings.Contains(w.Body.String(), tt.out)) // mismatch body
+		})
+	}
+}
+
+func TestHandler_ServeHTTP_JSON(t *testing.T) {
+	are := is.New(t)
+	h, err := server.New()
+	are.NoErr(err) // unexpected handler error
+	r := httptest.NewRequest(http.MethodPost, "/mysql?format=json&rows=1e6", strings.NewReader(schema))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	are.Equal(http.StatusOK, w.Code)                              // mismatch status code
+	are.Equal("application/json", w.Header().Get("Content-Type")) // mismatch content type
+	var res interface{}
+	are.NoErr(json.Unmarshal(w.Body.Bytes(), &res)) // invalid JSON
+}
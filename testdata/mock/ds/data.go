@@ -5,7 +5,10 @@
 package mock_ds
 
 import (
+	flag "flag"
 	gomock "github.com/golang/mock/gomock"
+	ds "github.com/rvflash/ds/pkg/ds"
+	io "io"
 	reflect "reflect"
 )
 
@@ -74,3 +77,471 @@ func (mr *MockDataMockRecorder) String() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "String", reflect.TypeOf((*MockData)(nil).String))
 }
+
+// MockScaler is a mock of Scaler interface
+type MockScaler struct {
+	ctrl     *gomock.Controller
+	recorder *MockScalerMockRecorder
+}
+
+// MockScalerMockRecorder is the mock recorder for MockScaler
+type MockScalerMockRecorder struct {
+	mock *MockScaler
+}
+
+// NewMockScaler creates a new mock instance
+func NewMockScaler(ctrl *gomock.Controller) *MockScaler {
+	mock := &MockScaler{ctrl: ctrl}
+	mock.recorder = &MockScalerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockScaler) EXPECT() *MockScalerMockRecorder {
+	return m.recorder
+}
+
+// Scale mocks base method
+func (m *MockScaler) Scale(rows uint64) (uint64, uint64) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scale", rows)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(uint64)
+	return ret0, ret1
+}
+
+// Scale indicates an expected call of Scale
+func (mr *MockScalerMockRecorder) Scale(rows interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scale", reflect.TypeOf((*MockScaler)(nil).Scale), rows)
+}
+
+// MockDistributor is a mock of Distributor interface
+type MockDistributor struct {
+	ctrl     *gomock.Controller
+	recorder *MockDistributorMockRecorder
+}
+
+// MockDistributorMockRecorder is the mock recorder for MockDistributor
+type MockDistributorMockRecorder struct {
+	mock *MockDistributor
+}
+
+// NewMockDistributor creates a new mock instance
+func NewMockDistributor(ctrl *gomock.Controller) *MockDistributor {
+	mock := &MockDistributor{ctrl: ctrl}
+	mock.recorder = &MockDistributorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDistributor) EXPECT() *MockDistributorMockRecorder {
+	return m.recorder
+}
+
+// Distribution mocks base method
+func (m *MockDistributor) Distribution() ds.Distribution {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Distribution")
+	ret0, _ := ret[0].(ds.Distribution)
+	return ret0
+}
+
+// Distribution indicates an expected call of Distribution
+func (mr *MockDistributorMockRecorder) Distribution() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Distribution", reflect.TypeOf((*MockDistributor)(nil).Distribution))
+}
+
+// MockNode is a mock of Node interface
+type MockNode struct {
+	ctrl     *gomock.Controller
+	recorder *MockNodeMockRecorder
+}
+
+// MockNodeMockRecorder is the mock recorder for MockNode
+type MockNodeMockRecorder struct {
+	mock *MockNode
+}
+
+// NewMockNode creates a new mock instance
+func NewMockNode(ctrl *gomock.Controller) *MockNode {
+	mock := &MockNode{ctrl: ctrl}
+	mock.recorder = &MockNodeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockNode) EXPECT() *MockNodeMockRecorder {
+	return m.recorder
+}
+
+// Size mocks base method
+func (m *MockNode) Size() (uint64, uint64) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Size")
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(uint64)
+	return ret0, ret1
+}
+
+// Size indicates an expected call of Size
+func (mr *MockNodeMockRecorder) Size() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Size", reflect.TypeOf((*MockNode)(nil).Size))
+}
+
+// Kind mocks base method
+func (m *MockNode) Kind() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Kind")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Kind indicates an expected call of Kind
+func (mr *MockNodeMockRecorder) Kind() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Kind", reflect.TypeOf((*MockNode)(nil).Kind))
+}
+
+// String mocks base method
+func (m *MockNode) String() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "String")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// String indicates an expected call of String
+func (mr *MockNodeMockRecorder) String() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "String", reflect.TypeOf((*MockNode)(nil).String))
+}
+
+// Children mocks base method
+func (m *MockNode) Children() []ds.Data {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Children")
+	ret0, _ := ret[0].([]ds.Data)
+	return ret0
+}
+
+// Children indicates an expected call of Children
+func (mr *MockNodeMockRecorder) Children() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Children", reflect.TypeOf((*MockNode)(nil).Children))
+}
+
+// MockGrouper is a mock of Grouper interface
+type MockGrouper struct {
+	ctrl     *gomock.Controller
+	recorder *MockGrouperMockRecorder
+}
+
+// MockGrouperMockRecorder is the mock recorder for MockGrouper
+type MockGrouperMockRecorder struct {
+	mock *MockGrouper
+}
+
+// NewMockGrouper creates a new mock instance
+func NewMockGrouper(ctrl *gomock.Controller) *MockGrouper {
+	mock := &MockGrouper{ctrl: ctrl}
+	mock.recorder = &MockGrouperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockGrouper) EXPECT() *MockGrouperMockRecorder {
+	return m.recorder
+}
+
+// Groups mocks base method
+func (m *MockGrouper) Groups() []ds.Node {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Groups")
+	ret0, _ := ret[0].([]ds.Node)
+	return ret0
+}
+
+// Groups indicates an expected call of Groups
+func (mr *MockGrouperMockRecorder) Groups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Groups", reflect.TypeOf((*MockGrouper)(nil).Groups))
+}
+
+// MockDetailer is a mock of Detailer interface
+type MockDetailer struct {
+	ctrl     *gomock.Controller
+	recorder *MockDetailerMockRecorder
+}
+
+// MockDetailerMockRecorder is the mock recorder for MockDetailer
+type MockDetailerMockRecorder struct {
+	mock *MockDetailer
+}
+
+// NewMockDetailer creates a new mock instance
+func NewMockDetailer(ctrl *gomock.Controller) *MockDetailer {
+	mock := &MockDetailer{ctrl: ctrl}
+	mock.recorder = &MockDetailerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDetailer) EXPECT() *MockDetailerMockRecorder {
+	return m.recorder
+}
+
+// Details mocks base method
+func (m *MockDetailer) Details() []ds.Data {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Details")
+	ret0, _ := ret[0].([]ds.Data)
+	return ret0
+}
+
+// Details indicates an expected call of Details
+func (mr *MockDetailerMockRecorder) Details() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Details", reflect.TypeOf((*MockDetailer)(nil).Details))
+}
+
+// MockFlagger is a mock of Flagger interface
+type MockFlagger struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlaggerMockRecorder
+}
+
+// MockFlaggerMockRecorder is the mock recorder for MockFlagger
+type MockFlaggerMockRecorder struct {
+	mock *MockFlagger
+}
+
+// NewMockFlagger creates a new mock instance
+func NewMockFlagger(ctrl *gomock.Controller) *MockFlagger {
+	mock := &MockFlagger{ctrl: ctrl}
+	mock.recorder = &MockFlaggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockFlagger) EXPECT() *MockFlaggerMockRecorder {
+	return m.recorder
+}
+
+// Flagged mocks base method
+func (m *MockFlagger) Flagged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flagged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Flagged indicates an expected call of Flagged
+func (mr *MockFlaggerMockRecorder) Flagged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flagged", reflect.TypeOf((*MockFlagger)(nil).Flagged))
+}
+
+// MockDescriber is a mock of Describer interface
+type MockDescriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockDescriberMockRecorder
+}
+
+// MockDescriberMockRecorder is the mock recorder for MockDescriber
+type MockDescriberMockRecorder struct {
+	mock *MockDescriber
+}
+
+// NewMockDescriber creates a new mock instance
+func NewMockDescriber(ctrl *gomock.Controller) *MockDescriber {
+	mock := &MockDescriber{ctrl: ctrl}
+	mock.recorder = &MockDescriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDescriber) EXPECT() *MockDescriberMockRecorder {
+	return m.recorder
+}
+
+// Property mocks base method
+func (m *MockDescriber) Property(name string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Property", name)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Property indicates an expected call of Property
+func (mr *MockDescriberMockRecorder) Property(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Property", reflect.TypeOf((*MockDescriber)(nil).Property), name)
+}
+
+// MockEstimator is a mock of Estimator interface
+type MockEstimator struct {
+	ctrl     *gomock.Controller
+	recorder *MockEstimatorMockRecorder
+}
+
+// MockEstimatorMockRecorder is the mock recorder for MockEstimator
+type MockEstimatorMockRecorder struct {
+	mock *MockEstimator
+}
+
+// NewMockEstimator creates a new mock instance
+func NewMockEstimator(ctrl *gomock.Controller) *MockEstimator {
+	mock := &MockEstimator{ctrl: ctrl}
+	mock.recorder = &MockEstimatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockEstimator) EXPECT() *MockEstimatorMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method
+func (m *MockEstimator) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name
+func (mr *MockEstimatorMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockEstimator)(nil).Name))
+}
+
+// Usage mocks base method
+func (m *MockEstimator) Usage() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Usage indicates an expected call of Usage
+func (mr *MockEstimatorMockRecorder) Usage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockEstimator)(nil).Usage))
+}
+
+// SetFlags mocks base method
+func (m *MockEstimator) SetFlags(fs *flag.FlagSet) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetFlags", fs)
+}
+
+// SetFlags indicates an expected call of SetFlags
+func (mr *MockEstimatorMockRecorder) SetFlags(fs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFlags", reflect.TypeOf((*MockEstimator)(nil).SetFlags), fs)
+}
+
+// Run mocks base method
+func (m *MockEstimator) Run(r io.Reader, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", r, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run
+func (mr *MockEstimatorMockRecorder) Run(r, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockEstimator)(nil).Run), r, w)
+}
+
+// MockConfigurable is a mock of Configurable interface
+type MockConfigurable struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigurableMockRecorder
+}
+
+// MockConfigurableMockRecorder is the mock recorder for MockConfigurable
+type MockConfigurableMockRecorder struct {
+	mock *MockConfigurable
+}
+
+// NewMockConfigurable creates a new mock instance
+func NewMockConfigurable(ctrl *gomock.Controller) *MockConfigurable {
+	mock := &MockConfigurable{ctrl: ctrl}
+	mock.recorder = &MockConfigurableMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockConfigurable) EXPECT() *MockConfigurableMockRecorder {
+	return m.recorder
+}
+
+// Settings mocks base method
+func (m *MockConfigurable) Settings() interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Settings")
+	ret0, _ := ret[0].(interface{})
+	return ret0
+}
+
+// Settings indicates an expected call of Settings
+func (mr *MockConfigurableMockRecorder) Settings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Settings", reflect.TypeOf((*MockConfigurable)(nil).Settings))
+}
+
+// MockWatcher is a mock of Watcher interface
+type MockWatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatcherMockRecorder
+}
+
+// MockWatcherMockRecorder is the mock recorder for MockWatcher
+type MockWatcherMockRecorder struct {
+	mock *MockWatcher
+}
+
+// NewMockWatcher creates a new mock instance
+func NewMockWatcher(ctrl *gomock.Controller) *MockWatcher {
+	mock := &MockWatcher{ctrl: ctrl}
+	mock.recorder = &MockWatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWatcher) EXPECT() *MockWatcherMockRecorder {
+	return m.recorder
+}
+
+// Watching mocks base method
+func (m *MockWatcher) Watching() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watching")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Watching indicates an expected call of Watching
+func (mr *MockWatcherMockRecorder) Watching() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watching", reflect.TypeOf((*MockWatcher)(nil).Watching))
+}
+
+// Watch mocks base method
+func (m *MockWatcher) Watch(paths []string, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", paths, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch
+func (mr *MockWatcherMockRecorder) Watch(paths, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockWatcher)(nil).Watch), paths, w)
+}
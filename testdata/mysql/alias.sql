@@ -0,0 +1,20 @@
+CREATE TABLE account (
+  id serial,
+  active bool NOT NULL DEFAULT 1,
+  enabled boolean,
+  code int4 unsigned,
+  counter int8 SERIAL DEFAULT VALUE,
+  tier middleint,
+  ratio double precision,
+  rate float4,
+  total fixed(10, 2),
+  name nchar(32) NOT NULL,
+  label national character varying(64),
+  alias nvarchar(32),
+  title character varying(128) charset latin1,
+  note long varchar,
+  raw long varbinary,
+  shape geomcollection,
+  email varchar(255) UNIQUE KEY
+) ENGINE=InnoDB;
+CREATE TABLE t (id int KEY, code char(3) UNIQUE) ENGINE=InnoDB;
@@ -0,0 +1,11 @@
+CREATE TABLE article (
+  id int NOT NULL,
+  title varchar(200) NOT NULL,
+  body text,
+  g geometry NOT NULL,
+  PRIMARY KEY (id),
+  FULLTEXT KEY ft_body (title, body) COMMENT 'ds: words=120 length=5',
+  FULLTEXT (title),
+  SPATIAL KEY sp (g)
+) ENGINE=InnoDB;
+CREATE TABLE m (id int NOT NULL, body text, PRIMARY KEY (id), FULLTEXT KEY ft (body)) ENGINE=MyISAM;
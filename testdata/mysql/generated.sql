@@ -0,0 +1,16 @@
+CREATE DATABASE account;
+
+CREATE TABLE `user` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `email` varchar(128) NOT NULL,
+  `first_name` varchar(32) DEFAULT NULL,
+  `last_name` varchar(32) DEFAULT NULL,
+  `full_name` varchar(65) GENERATED ALWAYS AS (concat(`first_name`, ' ', `last_name`)) VIRTUAL,
+  `domain` varchar(64) AS (substring_index(`email`, '@', -1)) STORED NOT NULL,
+  `secret` char(8) DEFAULT NULL INVISIBLE,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_full_name` (`full_name`),
+  KEY `idx_email` ((lower(`email`))),
+  KEY `idx_year` ((year(`created_at`)), `id`)
+) ENGINE=InnoDB;
@@ -0,0 +1,20 @@
+CREATE DATABASE logs;
+
+CREATE TABLE `event` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `year` smallint(4) unsigned NOT NULL,
+  `message` varchar(255) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`, `year`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8
+PARTITION BY RANGE (`year`) (
+  PARTITION p2019 VALUES LESS THAN (2020) COMMENT 'ds: weight=1',
+  PARTITION p2020 VALUES LESS THAN (2021) COMMENT 'ds: weight=3',
+  PARTITION pmax VALUES LESS THAN MAXVALUE COMMENT 'ds: weight=6'
+);
+
+CREATE TABLE `visit` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `site_id` int(10) unsigned NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB
+PARTITION BY HASH (`id`) PARTITIONS 4;
@@ -0,0 +1,10 @@
+CREATE DATABASE shop;
+CREATE TABLE customer (
+  id int NOT NULL AUTO_INCREMENT,
+  email varchar(255) NOT NULL, -- ds: avg=24
+  nickname varchar(64) DEFAULT NULL COMMENT 'ds: avg=12 null=0.4',
+  birth date DEFAULT NULL, -- ds: null=0.7
+  bio text,
+  PRIMARY KEY (id),
+  KEY idx_email (email)
+) ENGINE=InnoDB;
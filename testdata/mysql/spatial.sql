@@ -0,0 +1,13 @@
+CREATE TABLE place (
+  id int NOT NULL,
+  location point NOT NULL SRID 4326,
+  path linestring COMMENT 'ds: vertices=100',
+  area polygon,
+  zones multipolygon,
+  stops multipoint,
+  tracks multilinestring,
+  shape geometry,
+  items geomcollection,
+  PRIMARY KEY (id),
+  SPATIAL KEY sp_location (location)
+) ENGINE=InnoDB;